@@ -0,0 +1,145 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/nuklai/nuklaivm/storage"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+)
+
+// MaxBatchTransferEntries bounds how many transfers can be packed into a
+// single BatchTransfer, keeping its StateKeys/compute cost predictable.
+const MaxBatchTransferEntries = 64
+
+var (
+	// OutputBatchTransferEmpty is returned when a BatchTransfer carries no
+	// entries.
+	OutputBatchTransferEmpty = []byte("batch transfer must contain at least one entry")
+	// OutputBatchTransferTooLarge is returned when a BatchTransfer exceeds
+	// MaxBatchTransferEntries.
+	OutputBatchTransferTooLarge = []byte("batch transfer exceeds max entries")
+)
+
+var _ chain.Action = (*BatchTransfer)(nil)
+
+// TransferEntry is a single recipient/amount pair packed into a
+// BatchTransfer.
+type TransferEntry struct {
+	To    codec.Address `json:"to"`
+	Asset ids.ID        `json:"asset"`
+	Value uint64        `json:"value"`
+}
+
+// BatchTransfer moves funds to many recipients in a single transaction,
+// applying each entry in order exactly as repeated Transfer actions would.
+// Packing multiple transfers into one transaction amortizes the base
+// transaction fee across every entry instead of paying it once per transfer.
+type BatchTransfer struct {
+	Transfers []TransferEntry `json:"transfers"`
+}
+
+func (*BatchTransfer) GetTypeID() uint8 {
+	return nconsts.BatchTransferID
+}
+
+func (t *BatchTransfer) StateKeys(actor codec.Address, _ ids.ID) []string {
+	keys := make([]string, 0, 1+2*len(t.Transfers))
+	keys = append(keys, string(storage.BalanceKey(actor, ids.Empty)))
+	for _, tr := range t.Transfers {
+		keys = append(keys, string(storage.BalanceKey(actor, tr.Asset)), string(storage.BalanceKey(tr.To, tr.Asset)))
+	}
+	return keys
+}
+
+func (t *BatchTransfer) StateKeysMaxChunks() []uint16 {
+	chunks := make([]uint16, 0, 1+2*len(t.Transfers))
+	chunks = append(chunks, storage.BalanceChunks)
+	for range t.Transfers {
+		chunks = append(chunks, storage.BalanceChunks, storage.BalanceChunks)
+	}
+	return chunks
+}
+
+func (*BatchTransfer) OutputsWarpMessage() bool {
+	return false
+}
+
+func (t *BatchTransfer) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	units := batchTransferComputeUnits(len(t.Transfers))
+	if len(t.Transfers) == 0 {
+		return false, units, OutputBatchTransferEmpty, nil, nil
+	}
+	if len(t.Transfers) > MaxBatchTransferEntries {
+		return false, units, OutputBatchTransferTooLarge, nil, nil
+	}
+
+	for _, tr := range t.Transfers {
+		if err := storage.SubBalance(ctx, mu, actor, tr.Asset, tr.Value); err != nil {
+			return false, units, utils.ErrBytes(err), nil, nil
+		}
+		if err := storage.AddBalance(ctx, mu, tr.To, tr.Asset, tr.Value, true); err != nil {
+			return false, units, utils.ErrBytes(err), nil, nil
+		}
+	}
+	return true, units, nil, nil, nil
+}
+
+func (*BatchTransfer) MaxComputeUnits(chain.Rules) uint64 {
+	return batchTransferComputeUnits(MaxBatchTransferEntries)
+}
+
+// batchTransferComputeUnits scales linearly with the number of packed
+// transfers so a BatchTransfer costs no less than applying each transfer
+// individually would.
+func batchTransferComputeUnits(n int) uint64 {
+	return uint64(n)
+}
+
+func (t *BatchTransfer) Size() int {
+	return hconsts.Uint64Len + len(t.Transfers)*(codec.AddressLen+ids.IDLen+hconsts.Uint64Len)
+}
+
+func (t *BatchTransfer) Marshal(p *codec.Packer) {
+	p.PackUint64(uint64(len(t.Transfers)))
+	for _, tr := range t.Transfers {
+		p.PackAddress(tr.To)
+		p.PackID(tr.Asset)
+		p.PackUint64(tr.Value)
+	}
+}
+
+func UnmarshalBatchTransfer(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var t BatchTransfer
+	n := p.UnpackUint64(true)
+	t.Transfers = make([]TransferEntry, n)
+	for i := range t.Transfers {
+		p.UnpackAddress(&t.Transfers[i].To)
+		p.UnpackID(true, &t.Transfers[i].Asset)
+		t.Transfers[i].Value = p.UnpackUint64(true)
+	}
+	return &t, p.Err()
+}
+
+func (*BatchTransfer) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
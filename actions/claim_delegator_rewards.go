@@ -0,0 +1,119 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/emission"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+// ClaimDelegatorRewardsComputeUnits is the same fixed cost as
+// UndelegateUserStake: both touch exactly one DelegateUserStakeKey entry and
+// one emission call, the only difference being that this one never deletes
+// the entry.
+const ClaimDelegatorRewardsComputeUnits = UndelegateUserStakeComputeUnits
+
+var _ chain.Action = (*ClaimDelegatorRewards)(nil)
+
+// ClaimDelegatorRewards pays out a delegator's accrued rewards for NodeID
+// without touching the delegated principal, unlike UndelegateUserStake which
+// only ever pays rewards as part of exiting the position entirely.
+type ClaimDelegatorRewards struct {
+	NodeID []byte `json:"nodeID"` // Node ID of the validator delegated to
+}
+
+func (*ClaimDelegatorRewards) GetTypeID() uint8 {
+	return nconsts.ClaimDelegatorRewardsID
+}
+
+func (c *ClaimDelegatorRewards) StateKeys(actor codec.Address, _ ids.ID) []string {
+	nodeID, _ := ids.ToNodeID(c.NodeID)
+	return []string{
+		string(storage.BalanceKey(actor, ids.Empty)),
+		string(storage.DelegateUserStakeKey(actor, nodeID)),
+	}
+}
+
+func (*ClaimDelegatorRewards) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.BalanceChunks, storage.DelegateUserStakeChunks}
+}
+
+func (*ClaimDelegatorRewards) OutputsWarpMessage() bool {
+	return false
+}
+
+func (c *ClaimDelegatorRewards) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	nodeID, err := ids.ToNodeID(c.NodeID)
+	if err != nil {
+		return false, ClaimDelegatorRewardsComputeUnits, OutputInvalidNodeID, nil, nil
+	}
+
+	exists, _, _, _, rewardAddress, _, _, _ := storage.GetDelegateUserStake(ctx, mu, actor, nodeID)
+	if !exists {
+		return false, ClaimDelegatorRewardsComputeUnits, OutputUserNotStaked, nil, nil
+	}
+
+	// ClaimDelegatorRewards snapshots the reward accumulator and resets the
+	// delegator's checkpoint in the emission balancer; the stake record
+	// itself (principal, StakeEndBlock, AutoCompound) is untouched.
+	emissionInstance := emission.GetEmission()
+	rewardAmount, err := emissionInstance.ClaimDelegatorRewards(nodeID, actor)
+	if err != nil {
+		return false, ClaimDelegatorRewardsComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	if err := storage.AddBalance(ctx, mu, rewardAddress, ids.Empty, rewardAmount, true); err != nil {
+		return false, ClaimDelegatorRewardsComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	sr := &RegisterStakeResult{rewardAmount}
+	output, err := sr.Marshal()
+	if err != nil {
+		return false, ClaimDelegatorRewardsComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	return true, ClaimDelegatorRewardsComputeUnits, output, nil, nil
+}
+
+func (*ClaimDelegatorRewards) MaxComputeUnits(chain.Rules) uint64 {
+	return ClaimDelegatorRewardsComputeUnits
+}
+
+func (*ClaimDelegatorRewards) Size() int {
+	return hconsts.NodeIDLen
+}
+
+func (c *ClaimDelegatorRewards) Marshal(p *codec.Packer) {
+	p.PackBytes(c.NodeID)
+}
+
+func UnmarshalClaimDelegatorRewards(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var claim ClaimDelegatorRewards
+	p.UnpackBytes(hconsts.NodeIDLen, true, &claim.NodeID)
+	return &claim, p.Err()
+}
+
+func (*ClaimDelegatorRewards) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -27,6 +27,10 @@ type DelegateUserStake struct {
 	StakeEndBlock   uint64        `json:"stakeEndBlock"`   // Block height at which the stake should end
 	StakedAmount    uint64        `json:"stakedAmount"`    // Amount of NAI staked
 	RewardAddress   codec.Address `json:"rewardAddress"`   // Address to receive rewards
+	// AutoCompound folds rewards back into StakedAmount each epoch via
+	// emission.EndBlock's sweep instead of leaving them to be claimed with
+	// ClaimDelegatorRewards or paid out on exit.
+	AutoCompound bool `json:"autoCompound"`
 }
 
 func (*DelegateUserStake) GetTypeID() uint8 {
@@ -71,7 +75,7 @@ func (s *DelegateUserStake) Execute(
 	}
 
 	// Check if the user has already delegated to this validator node before
-	exists, _, _, _, _, _, _ = storage.GetDelegateUserStake(ctx, mu, actor, nodeID)
+	exists, _, _, _, _, _, _, _ = storage.GetDelegateUserStake(ctx, mu, actor, nodeID)
 	if exists {
 		return false, DelegateUserStakeComputeUnits, OutputUserAlreadyStaked, nil, nil
 	}
@@ -105,7 +109,7 @@ func (s *DelegateUserStake) Execute(
 	if err := storage.SubBalance(ctx, mu, actor, ids.Empty, s.StakedAmount); err != nil {
 		return false, DelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
 	}
-	if err := storage.SetDelegateUserStake(ctx, mu, actor, nodeID, s.StakeStartBlock, s.StakeEndBlock, s.StakedAmount, s.RewardAddress); err != nil {
+	if err := storage.SetDelegateUserStake(ctx, mu, actor, nodeID, s.StakeStartBlock, s.StakeEndBlock, s.StakedAmount, s.RewardAddress, s.AutoCompound); err != nil {
 		return false, DelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
 	}
 	return true, DelegateUserStakeComputeUnits, nil, nil, nil
@@ -116,7 +120,7 @@ func (*DelegateUserStake) MaxComputeUnits(chain.Rules) uint64 {
 }
 
 func (*DelegateUserStake) Size() int {
-	return hconsts.NodeIDLen + 3*hconsts.Uint64Len + codec.AddressLen
+	return hconsts.NodeIDLen + 3*hconsts.Uint64Len + codec.AddressLen + hconsts.BoolLen
 }
 
 func (s *DelegateUserStake) Marshal(p *codec.Packer) {
@@ -125,6 +129,7 @@ func (s *DelegateUserStake) Marshal(p *codec.Packer) {
 	p.PackUint64(s.StakeEndBlock)
 	p.PackUint64(s.StakedAmount)
 	p.PackAddress(s.RewardAddress)
+	p.PackBool(s.AutoCompound)
 }
 
 func UnmarshalDelegateUserStake(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
@@ -134,6 +139,7 @@ func UnmarshalDelegateUserStake(p *codec.Packer, _ *warp.Message) (chain.Action,
 	stake.StakeEndBlock = p.UnpackUint64(true)
 	stake.StakedAmount = p.UnpackUint64(true)
 	p.UnpackAddress(&stake.RewardAddress)
+	stake.AutoCompound = p.UnpackBool()
 	return &stake, p.Err()
 }
 
@@ -0,0 +1,241 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	hmath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/nuklai/nuklaivm/storage"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+)
+
+// ExportAssetComputeUnits is a fixed cost independent of Route length: Route
+// is carried for observability only and never walked during Execute (the
+// relayer, not the chain, decides where a multi-hop transfer goes next).
+const ExportAssetComputeUnits = 5
+
+var (
+	// OutputMustReturnImportedAsset is returned when Return is false but
+	// Asset is itself an asset ImportAsset minted on this chain: an asset
+	// that originated elsewhere can only leave this chain as a return
+	// transfer, never as if this chain were its origin.
+	OutputMustReturnImportedAsset = []byte("must set return when exporting an imported asset")
+	// OutputAssetNotImported is returned when Return is true but Asset
+	// isn't an asset ImportAsset minted on this chain, so there's nothing
+	// to unwind.
+	OutputAssetNotImported = []byte("return set but asset was not imported")
+	// OutputCorruptImportedAssetMetadata is returned when Asset is marked
+	// as imported but its metadata doesn't decode as an origin (asset,
+	// sourceChainID) pair; this should never happen outside of a storage
+	// bug, since only ImportAsset ever sets this metadata.
+	OutputCorruptImportedAssetMetadata = []byte("imported asset metadata is corrupt")
+)
+
+var _ chain.Action = (*ExportAsset)(nil)
+
+// ExportAsset locks or burns Asset on this chain and emits an unsigned warp
+// message a relayer aggregates validator signatures for and delivers to
+// Destination as an ImportAsset. A forward transfer (Return false) locks a
+// native asset behind a loan Destination owes this chain; a return transfer
+// (Return true) burns a previously imported asset and unwinds the loan the
+// asset's true origin chain is carrying for it.
+type ExportAsset struct {
+	// To is the address ImportAsset credits on Destination.
+	To codec.Address `json:"to"`
+	// Asset is the asset being exported, as this chain knows it: the
+	// native asset ID for a forward transfer, or the locally-imported
+	// asset ID for a return transfer.
+	Asset ids.ID `json:"asset"`
+	// Value is the amount of Asset to export.
+	Value uint64 `json:"value"`
+	// Return marks this as unwinding an earlier forward transfer of Asset
+	// back to its origin chain, rather than a new transfer away from it.
+	Return bool `json:"return"`
+	// Destination is the blockchain ID the emitted warp message should be
+	// relayed to.
+	Destination ids.ID `json:"destination"`
+	// Reward is paid to To alongside Value when the transfer lands,
+	// incentivizing someone to relay it promptly. Only meaningful on a
+	// return transfer, where it's paid out of the loan being unwound.
+	Reward uint64 `json:"reward"`
+
+	// SwapIn, AssetOut, SwapOut, and SwapExpiry attach an optional atomic
+	// swap order to the transfer; see warpTransfer for their semantics.
+	SwapIn     uint64 `json:"swapIn"`
+	AssetOut   ids.ID `json:"assetOut"`
+	SwapOut    uint64 `json:"swapOut"`
+	SwapExpiry int64  `json:"swapExpiry"`
+
+	// Route records the chain(s) a relayer has already carried this
+	// transfer across on its way to Destination, for observability; the
+	// relayer alone decides where a multi-hop transfer goes next.
+	Route []ids.ID `json:"route,omitempty"`
+}
+
+func (*ExportAsset) GetTypeID() uint8 {
+	return nconsts.ExportAssetID
+}
+
+func (t *ExportAsset) StateKeys(actor codec.Address, _ ids.ID) []string {
+	keys := []string{
+		string(storage.BalanceKey(actor, t.Asset)),
+		string(storage.AssetKey(t.Asset)),
+	}
+	if !t.Return {
+		keys = append(keys, string(storage.LoanKey(t.Asset, t.Destination)))
+	}
+	return keys
+}
+
+func (t *ExportAsset) StateKeysMaxChunks() []uint16 {
+	chunks := []uint16{storage.BalanceChunks, storage.AssetChunks}
+	if !t.Return {
+		chunks = append(chunks, storage.LoanChunks)
+	}
+	return chunks
+}
+
+func (*ExportAsset) OutputsWarpMessage() bool {
+	return true
+}
+
+func (t *ExportAsset) Execute(
+	ctx context.Context,
+	rules chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	actionID ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	exists, symbol, decimals, metadata, supply, _, isWarp, err := storage.GetAsset(ctx, mu, t.Asset)
+	if err != nil {
+		return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	payloadAsset := t.Asset
+	if !t.Return {
+		if exists && isWarp {
+			return false, ExportAssetComputeUnits, OutputMustReturnImportedAsset, nil, nil
+		}
+
+		if err := storage.SubBalance(ctx, mu, actor, t.Asset, t.Value); err != nil {
+			return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		}
+		if err := storage.AddLoan(ctx, mu, t.Asset, t.Destination, t.Value); err != nil {
+			return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		}
+	} else {
+		if !exists || !isWarp {
+			return false, ExportAssetComputeUnits, OutputAssetNotImported, nil, nil
+		}
+		origin, _, ok := unmarshalImportedAssetMetadata(metadata)
+		if !ok {
+			return false, ExportAssetComputeUnits, OutputCorruptImportedAssetMetadata, nil, nil
+		}
+		payloadAsset = origin
+
+		burn, err := hmath.Add64(t.Value, t.Reward)
+		if err != nil {
+			return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		}
+		if err := storage.SubBalance(ctx, mu, actor, t.Asset, burn); err != nil {
+			return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+		}
+		if burn >= supply {
+			if err := storage.DeleteAsset(ctx, mu, t.Asset); err != nil {
+				return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+			}
+		} else {
+			if err := storage.SetAsset(ctx, mu, t.Asset, symbol, decimals, metadata, supply-burn, codec.EmptyAddress, true); err != nil {
+				return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+			}
+		}
+	}
+
+	transfer := &warpTransfer{
+		To:         t.To,
+		Asset:      payloadAsset,
+		Value:      t.Value,
+		Return:     t.Return,
+		Reward:     t.Reward,
+		TxID:       actionID,
+		SwapIn:     t.SwapIn,
+		AssetOut:   t.AssetOut,
+		SwapOut:    t.SwapOut,
+		SwapExpiry: t.SwapExpiry,
+	}
+	payload, err := transfer.Marshal()
+	if err != nil {
+		return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	unsigned, err := warp.NewUnsignedMessage(rules.NetworkID(), rules.ChainID(), payload)
+	if err != nil {
+		return false, ExportAssetComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	return true, ExportAssetComputeUnits, nil, unsigned, nil
+}
+
+func (*ExportAsset) MaxComputeUnits(chain.Rules) uint64 {
+	return ExportAssetComputeUnits
+}
+
+func (t *ExportAsset) Size() int {
+	// To, Asset, Value, Return, Destination, Reward, SwapIn, AssetOut,
+	// SwapOut, SwapExpiry, len(Route), Route...
+	return codec.AddressLen + ids.IDLen + hconsts.Uint64Len + hconsts.Uint64Len + ids.IDLen +
+		hconsts.Uint64Len + hconsts.Uint64Len + ids.IDLen + hconsts.Uint64Len + hconsts.Uint64Len +
+		hconsts.Uint64Len + len(t.Route)*ids.IDLen
+}
+
+func (t *ExportAsset) Marshal(p *codec.Packer) {
+	p.PackAddress(t.To)
+	p.PackID(t.Asset)
+	p.PackUint64(t.Value)
+	p.PackUint64(boolToUint64(t.Return))
+	p.PackID(t.Destination)
+	p.PackUint64(t.Reward)
+	p.PackUint64(t.SwapIn)
+	p.PackID(t.AssetOut)
+	p.PackUint64(t.SwapOut)
+	p.PackUint64(uint64(t.SwapExpiry))
+	p.PackUint64(uint64(len(t.Route)))
+	for _, hop := range t.Route {
+		p.PackID(hop)
+	}
+}
+
+func UnmarshalExportAsset(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var t ExportAsset
+	p.UnpackAddress(&t.To)
+	p.UnpackID(true, &t.Asset)
+	t.Value = p.UnpackUint64(true)
+	t.Return = p.UnpackUint64(false) != 0
+	p.UnpackID(true, &t.Destination)
+	t.Reward = p.UnpackUint64(false)
+	t.SwapIn = p.UnpackUint64(false)
+	p.UnpackID(false, &t.AssetOut)
+	t.SwapOut = p.UnpackUint64(false)
+	t.SwapExpiry = int64(p.UnpackUint64(false))
+	n := p.UnpackUint64(false)
+	t.Route = make([]ids.ID, n)
+	for i := range t.Route {
+		p.UnpackID(true, &t.Route[i])
+	}
+	return &t, p.Err()
+}
+
+func (*ExportAsset) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
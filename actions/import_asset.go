@@ -0,0 +1,258 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	hmath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/nuklai/nuklaivm/storage"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+)
+
+// ImportAssetComputeUnits is a fixed cost for landing a transfer, on top of
+// whatever extra work filling an attached swap order costs.
+const ImportAssetComputeUnits = 5
+
+// ImportAssetSwapFillComputeUnits is charged on top of
+// ImportAssetComputeUnits when Fill lands an attached swap order.
+const ImportAssetSwapFillComputeUnits = 5
+
+var (
+	// OutputWarpMessageAlreadyDelivered is returned when the warp message
+	// ImportAsset is relaying has already been imported once before.
+	OutputWarpMessageAlreadyDelivered = []byte("warp message was already imported")
+	// OutputSwapExpired is returned when Fill is set but the transfer's
+	// attached swap order has already expired.
+	OutputSwapExpired = []byte("swap order has expired")
+)
+
+var _ chain.Action = (*ImportAsset)(nil)
+
+// ImportAsset is the destination-side half of asset bridging: it takes a
+// verified warp message previously emitted by ExportAsset and lands the
+// transfer it describes, minting (or crediting, for a return transfer) the
+// imported asset and unwinding whatever loan the transfer corresponds to.
+// Every field but Fill is recovered entirely from the warp message by
+// UnmarshalImportAsset, the same way ReceiveMessage derives its fields from
+// the SendMessage it's relaying.
+//
+// ImportAsset intentionally carries no caller-supplied quorum threshold
+// (e.g. a MinWeightNumerator/Denominator field): the warp message's
+// signature weight is already checked against the chain's own fixed,
+// genesis-configured quorum before Execute ever runs, by the same warp
+// verification every other warp-carrying action goes through. A relayer's
+// MinStakeWeightPercent (relayer/config.go) only decides when that relayer
+// bothers to submit; it has no bearing on what the chain will accept.
+// Letting a transaction pick its own threshold here would let any submitter
+// choose an arbitrarily low one and import an under-attested transfer, so
+// that knob isn't exposed.
+type ImportAsset struct {
+	// Fill, when true, uses the actor's own balance to fill the transfer's
+	// attached atomic swap order (if any, and not yet expired): the actor
+	// pays SwapOut of AssetOut to To in exchange for SwapIn of the
+	// imported Value, instead of the entire Value landing on To alone.
+	Fill bool `json:"fill"`
+
+	// SourceChainID is the blockchain ID the warp message originated from.
+	SourceChainID ids.ID
+	// TxID is the ExportAsset transaction that produced this transfer.
+	TxID ids.ID
+	// To is the address this transfer credits.
+	To codec.Address
+	// Asset is the transfer's native asset identity: see warpTransfer.Asset.
+	Asset ids.ID
+	// Value is the amount of Asset being transferred, excluding Reward.
+	Value uint64
+	// Return marks this transfer as unwinding an earlier forward transfer.
+	Return bool
+	// Reward is paid out alongside Value.
+	Reward uint64
+
+	SwapIn     uint64
+	AssetOut   ids.ID
+	SwapOut    uint64
+	SwapExpiry int64
+}
+
+func (*ImportAsset) GetTypeID() uint8 {
+	return nconsts.ImportAssetID
+}
+
+func (t *ImportAsset) importedAssetID() ids.ID {
+	return ImportedAssetID(t.Asset, t.SourceChainID)
+}
+
+func (t *ImportAsset) StateKeys(actor codec.Address, _ ids.ID) []string {
+	asset := t.Asset
+	if !t.Return {
+		asset = t.importedAssetID()
+	}
+	keys := []string{
+		string(storage.IncomingWarpMessageKey(t.SourceChainID, t.TxID)),
+		string(storage.BalanceKey(t.To, asset)),
+		string(storage.AssetKey(asset)),
+	}
+	if t.Return {
+		keys = append(keys, string(storage.LoanKey(t.Asset, t.SourceChainID)))
+	}
+	if t.Fill {
+		keys = append(keys,
+			string(storage.BalanceKey(actor, asset)),
+			string(storage.BalanceKey(actor, t.AssetOut)),
+			string(storage.BalanceKey(t.To, t.AssetOut)),
+		)
+	}
+	return keys
+}
+
+func (t *ImportAsset) StateKeysMaxChunks() []uint16 {
+	chunks := []uint16{storage.IncomingWarpMessageChunks, storage.BalanceChunks, storage.AssetChunks}
+	if t.Return {
+		chunks = append(chunks, storage.LoanChunks)
+	}
+	if t.Fill {
+		chunks = append(chunks, storage.BalanceChunks, storage.BalanceChunks, storage.BalanceChunks)
+	}
+	return chunks
+}
+
+func (*ImportAsset) OutputsWarpMessage() bool {
+	return false
+}
+
+func (t *ImportAsset) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	units := uint64(ImportAssetComputeUnits)
+
+	delivered, err := storage.HasIncomingWarpMessage(ctx, mu, t.SourceChainID, t.TxID)
+	if err != nil {
+		return false, units, utils.ErrBytes(err), nil, nil
+	}
+	if delivered {
+		return false, units, OutputWarpMessageAlreadyDelivered, nil, nil
+	}
+	if err := storage.SetIncomingWarpMessage(ctx, mu, t.SourceChainID, t.TxID); err != nil {
+		return false, units, utils.ErrBytes(err), nil, nil
+	}
+
+	total, err := hmath.Add64(t.Value, t.Reward)
+	if err != nil {
+		return false, units, utils.ErrBytes(err), nil, nil
+	}
+
+	if t.Return {
+		if err := storage.SubLoan(ctx, mu, t.Asset, t.SourceChainID, total); err != nil {
+			return false, units, utils.ErrBytes(err), nil, nil
+		}
+		if err := storage.AddBalance(ctx, mu, t.To, t.Asset, total, true); err != nil {
+			return false, units, utils.ErrBytes(err), nil, nil
+		}
+		return true, units, nil, nil, nil
+	}
+
+	newAsset := t.importedAssetID()
+	exists, symbol, decimals, metadata, supply, _, _, err := storage.GetAsset(ctx, mu, newAsset)
+	if err != nil {
+		return false, units, utils.ErrBytes(err), nil, nil
+	}
+	if !exists {
+		symbol, decimals, metadata = []byte(nconsts.Symbol), nconsts.Decimals, ImportedAssetMetadata(t.Asset, t.SourceChainID)
+	}
+	newSupply, err := hmath.Add64(supply, total)
+	if err != nil {
+		return false, units, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetAsset(ctx, mu, newAsset, symbol, decimals, metadata, newSupply, codec.EmptyAddress, true); err != nil {
+		return false, units, utils.ErrBytes(err), nil, nil
+	}
+
+	toCredit := total
+	if t.Fill && t.hasSwap() {
+		if t.SwapExpiry < timestamp {
+			return false, units, OutputSwapExpired, nil, nil
+		}
+		units += ImportAssetSwapFillComputeUnits
+		if err := storage.SubBalance(ctx, mu, actor, t.AssetOut, t.SwapOut); err != nil {
+			return false, units, utils.ErrBytes(err), nil, nil
+		}
+		if err := storage.AddBalance(ctx, mu, t.To, t.AssetOut, t.SwapOut, true); err != nil {
+			return false, units, utils.ErrBytes(err), nil, nil
+		}
+		if err := storage.AddBalance(ctx, mu, actor, newAsset, t.SwapIn, true); err != nil {
+			return false, units, utils.ErrBytes(err), nil, nil
+		}
+		toCredit -= t.SwapIn
+	}
+	if err := storage.AddBalance(ctx, mu, t.To, newAsset, toCredit, true); err != nil {
+		return false, units, utils.ErrBytes(err), nil, nil
+	}
+
+	return true, units, nil, nil, nil
+}
+
+// hasSwap reports whether t carries an atomic swap order for Fill to
+// optionally land.
+func (t *ImportAsset) hasSwap() bool {
+	return t.SwapIn > 0
+}
+
+func (*ImportAsset) MaxComputeUnits(chain.Rules) uint64 {
+	return ImportAssetComputeUnits + ImportAssetSwapFillComputeUnits
+}
+
+func (*ImportAsset) Size() int {
+	return hconsts.Uint64Len
+}
+
+func (t *ImportAsset) Marshal(p *codec.Packer) {
+	p.PackUint64(boolToUint64(t.Fill))
+}
+
+// UnmarshalImportAsset builds an ImportAsset from the verified warp message
+// w; only Fill comes from the transaction itself.
+func UnmarshalImportAsset(p *codec.Packer, w *warp.Message) (chain.Action, error) {
+	var t ImportAsset
+	t.Fill = p.UnpackUint64(false) != 0
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+
+	transfer, err := unmarshalWarpTransfer(w.Payload)
+	if err != nil {
+		return nil, err
+	}
+	t.SourceChainID = w.SourceChainID
+	t.TxID = transfer.TxID
+	t.To = transfer.To
+	t.Asset = transfer.Asset
+	t.Value = transfer.Value
+	t.Return = transfer.Return
+	t.Reward = transfer.Reward
+	t.SwapIn = transfer.SwapIn
+	t.AssetOut = transfer.AssetOut
+	t.SwapOut = transfer.SwapOut
+	t.SwapExpiry = transfer.SwapExpiry
+	return &t, nil
+}
+
+func (*ImportAsset) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"crypto/sha256"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// ImportedAssetID derives the asset ID ImportAsset mints on this chain for a
+// forward transfer of asset from sourceChainID. Deriving it rather than
+// letting the importer pick one means every import of the same origin
+// asset, from anyone, lands on the same local asset ID.
+func ImportedAssetID(asset, sourceChainID ids.ID) ids.ID {
+	return ids.ID(sha256.Sum256(ImportedAssetMetadata(asset, sourceChainID)))
+}
+
+// ImportedAssetMetadata is the metadata storage.SetAsset records for an
+// asset ImportAsset mints, encoding exactly the (asset, sourceChainID) pair
+// ExportAsset needs to recover when that asset is later exported back with
+// Return set.
+func ImportedAssetMetadata(asset, sourceChainID ids.ID) []byte {
+	b := make([]byte, 2*ids.IDLen)
+	copy(b, asset[:])
+	copy(b[ids.IDLen:], sourceChainID[:])
+	return b
+}
+
+// unmarshalImportedAssetMetadata recovers the (asset, sourceChainID) pair
+// ImportedAssetMetadata encoded. ok is false if metadata wasn't produced by
+// ImportedAssetMetadata.
+func unmarshalImportedAssetMetadata(metadata []byte) (asset, sourceChainID ids.ID, ok bool) {
+	if len(metadata) != 2*ids.IDLen {
+		return ids.Empty, ids.Empty, false
+	}
+	copy(asset[:], metadata[:ids.IDLen])
+	copy(sourceChainID[:], metadata[ids.IDLen:])
+	return asset, sourceChainID, true
+}
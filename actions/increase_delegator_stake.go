@@ -0,0 +1,142 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	hmath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/emission"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+// IncreaseDelegatorStakeComputeUnits is DelegateUserStake's cost: topping up
+// an existing position touches the same DelegateUserStakeKey entry and makes
+// the same single emission call DelegateUserStake does.
+const IncreaseDelegatorStakeComputeUnits = DelegateUserStakeComputeUnits
+
+var _ chain.Action = (*IncreaseDelegatorStake)(nil)
+
+// IncreaseDelegatorStake adds AdditionalAmount to an existing delegation to
+// NodeID, optionally extending it to NewStakeEndBlock, without the
+// undelegate-then-redelegate round trip DelegateUserStake alone would
+// require. A zero NewStakeEndBlock leaves the existing StakeEndBlock as-is.
+type IncreaseDelegatorStake struct {
+	NodeID           []byte `json:"nodeID"`           // Node ID of the validator already delegated to
+	AdditionalAmount uint64 `json:"additionalAmount"` // Amount of NAI to add to the existing stake
+	NewStakeEndBlock uint64 `json:"newStakeEndBlock"` // 0 leaves StakeEndBlock unchanged
+}
+
+func (*IncreaseDelegatorStake) GetTypeID() uint8 {
+	return nconsts.IncreaseDelegatorStakeID
+}
+
+func (s *IncreaseDelegatorStake) StateKeys(actor codec.Address, _ ids.ID) []string {
+	nodeID, _ := ids.ToNodeID(s.NodeID)
+	return []string{
+		string(storage.BalanceKey(actor, ids.Empty)),
+		string(storage.DelegateUserStakeKey(actor, nodeID)),
+		string(storage.RegisterValidatorStakeKey(nodeID)),
+	}
+}
+
+func (*IncreaseDelegatorStake) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.BalanceChunks, storage.DelegateUserStakeChunks, storage.RegisterValidatorStakeChunks}
+}
+
+func (*IncreaseDelegatorStake) OutputsWarpMessage() bool {
+	return false
+}
+
+func (s *IncreaseDelegatorStake) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	nodeID, err := ids.ToNodeID(s.NodeID)
+	if err != nil {
+		return false, IncreaseDelegatorStakeComputeUnits, OutputInvalidNodeID, nil, nil
+	}
+
+	if s.AdditionalAmount == 0 {
+		return false, IncreaseDelegatorStakeComputeUnits, OutputDelegateStakedAmountInvalid, nil, nil
+	}
+
+	exists, stakeStartBlock, stakeEndBlock, stakedAmount, rewardAddress, _, autoCompound, _ := storage.GetDelegateUserStake(ctx, mu, actor, nodeID)
+	if !exists {
+		return false, IncreaseDelegatorStakeComputeUnits, OutputUserNotStaked, nil, nil
+	}
+
+	if s.NewStakeEndBlock != 0 {
+		if s.NewStakeEndBlock <= stakeEndBlock {
+			return false, IncreaseDelegatorStakeComputeUnits, OutputInvalidStakeEndBlock, nil, nil
+		}
+		stakeEndBlock = s.NewStakeEndBlock
+	}
+
+	newStakedAmount, err := hmath.Add64(stakedAmount, s.AdditionalAmount)
+	if err != nil {
+		return false, IncreaseDelegatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	emissionInstance := emission.GetEmission()
+	if err := emissionInstance.IncreaseDelegatorStake(nodeID, actor, s.AdditionalAmount, stakeEndBlock); err != nil {
+		return false, IncreaseDelegatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	if err := storage.SubBalance(ctx, mu, actor, ids.Empty, s.AdditionalAmount); err != nil {
+		return false, IncreaseDelegatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.UpdateDelegateUserStake(ctx, mu, actor, nodeID, stakeStartBlock, stakeEndBlock, newStakedAmount, rewardAddress, autoCompound); err != nil {
+		return false, IncreaseDelegatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	sr := &RegisterStakeResult{newStakedAmount}
+	output, err := sr.Marshal()
+	if err != nil {
+		return false, IncreaseDelegatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	return true, IncreaseDelegatorStakeComputeUnits, output, nil, nil
+}
+
+func (*IncreaseDelegatorStake) MaxComputeUnits(chain.Rules) uint64 {
+	return IncreaseDelegatorStakeComputeUnits
+}
+
+func (*IncreaseDelegatorStake) Size() int {
+	return hconsts.NodeIDLen + 2*hconsts.Uint64Len
+}
+
+func (s *IncreaseDelegatorStake) Marshal(p *codec.Packer) {
+	p.PackBytes(s.NodeID)
+	p.PackUint64(s.AdditionalAmount)
+	p.PackUint64(s.NewStakeEndBlock)
+}
+
+func UnmarshalIncreaseDelegatorStake(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var stake IncreaseDelegatorStake
+	p.UnpackBytes(hconsts.NodeIDLen, true, &stake.NodeID)
+	stake.AdditionalAmount = p.UnpackUint64(true)
+	stake.NewStakeEndBlock = p.UnpackUint64(false)
+	return &stake, p.Err()
+}
+
+func (*IncreaseDelegatorStake) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
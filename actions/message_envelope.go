@@ -0,0 +1,38 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+)
+
+// messageEnvelope is the wire format SendMessage packs into the warp
+// message it emits, and ReceiveMessage unpacks from the verified warp
+// message it consumes. It carries the fields a generic ICM primitive needs
+// on top of the payload itself: who sent it, and which registered
+// icm.Receiver it's addressed to.
+type messageEnvelope struct {
+	Sender   codec.Address
+	Receiver codec.Address
+	Payload  []byte
+}
+
+func (e *messageEnvelope) Marshal() ([]byte, error) {
+	size := 2*codec.AddressLen + hconsts.Uint32Len + len(e.Payload)
+	p := codec.NewWriter(size, size)
+	p.PackAddress(e.Sender)
+	p.PackAddress(e.Receiver)
+	p.PackBytes(e.Payload)
+	return p.Bytes(), p.Err()
+}
+
+func unmarshalMessageEnvelope(b []byte) (*messageEnvelope, error) {
+	p := codec.NewReader(b, 2*codec.AddressLen+hconsts.Uint32Len+MaxMessagePayloadSize)
+	var e messageEnvelope
+	p.UnpackAddress(&e.Sender)
+	p.UnpackAddress(&e.Receiver)
+	p.UnpackBytes(MaxMessagePayloadSize, false, &e.Payload)
+	return &e, p.Err()
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/icm"
+)
+
+// ReceiveMessageBaseComputeUnits is the fixed cost of dispatching to a
+// Receiver, charged even if lookup fails, on top of whatever that Receiver
+// itself spends.
+const ReceiveMessageBaseComputeUnits = 1
+
+// MaxReceiverComputeUnits bounds how much compute a single icm.Receiver may
+// spend handling one message, so ReceiveMessage's MaxComputeUnits stays a
+// fixed, predictable bound regardless of which Receiver a message targets.
+const MaxReceiverComputeUnits = 1000
+
+// OutputReceiverNotRegistered is returned when a ReceiveMessage's Receiver
+// has no icm.Receiver registered for it on this chain.
+var OutputReceiverNotRegistered = []byte("no receiver registered for this message")
+
+var _ chain.Action = (*ReceiveMessage)(nil)
+
+// ReceiveMessage is the destination-side half of the generic ICM primitive:
+// it takes a verified warp message previously emitted by SendMessage and
+// dispatches its payload to whatever icm.Receiver is registered for the
+// message's Receiver address, the same way ImportAsset lands an
+// ExportAsset. Its fields are never supplied directly by a caller; they are
+// recovered entirely from the warp message by UnmarshalReceiveMessage, the
+// same way ImportAsset derives its fields from the export it's relaying.
+type ReceiveMessage struct {
+	// Source is the blockchain ID the warp message originated from.
+	Source ids.ID
+	// Sender is the address that submitted the original SendMessage on
+	// Source.
+	Sender codec.Address
+	// Receiver identifies the icm.Receiver this message is dispatched to.
+	Receiver codec.Address
+	// Payload is passed to Receiver.Receive unmodified.
+	Payload []byte
+}
+
+func (*ReceiveMessage) GetTypeID() uint8 {
+	return nconsts.ReceiveMessageID
+}
+
+func (r *ReceiveMessage) StateKeys(_ codec.Address, _ ids.ID) []string {
+	receiver, ok := icm.Lookup(r.Receiver)
+	if !ok {
+		return nil
+	}
+	return receiver.StateKeys(r.Payload)
+}
+
+func (r *ReceiveMessage) StateKeysMaxChunks() []uint16 {
+	receiver, ok := icm.Lookup(r.Receiver)
+	if !ok {
+		return nil
+	}
+	return receiver.StateKeysMaxChunks(r.Payload)
+}
+
+func (*ReceiveMessage) OutputsWarpMessage() bool {
+	return false
+}
+
+func (r *ReceiveMessage) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	_ codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	receiver, ok := icm.Lookup(r.Receiver)
+	if !ok {
+		return false, ReceiveMessageBaseComputeUnits, OutputReceiverNotRegistered, nil, nil
+	}
+
+	units, output, err := receiver.Receive(ctx, mu, r.Source, r.Sender, r.Payload, MaxReceiverComputeUnits)
+	total := ReceiveMessageBaseComputeUnits + units
+	if err != nil {
+		return false, total, utils.ErrBytes(err), nil, nil
+	}
+	return true, total, output, nil, nil
+}
+
+func (*ReceiveMessage) MaxComputeUnits(chain.Rules) uint64 {
+	return ReceiveMessageBaseComputeUnits + MaxReceiverComputeUnits
+}
+
+func (*ReceiveMessage) Size() int {
+	return 0
+}
+
+func (*ReceiveMessage) Marshal(*codec.Packer) {}
+
+// UnmarshalReceiveMessage builds a ReceiveMessage entirely from the verified
+// warp message w; ReceiveMessage carries no fields of its own in the
+// transaction itself, the same as ImportAsset.
+func UnmarshalReceiveMessage(_ *codec.Packer, w *warp.Message) (chain.Action, error) {
+	envelope, err := unmarshalMessageEnvelope(w.Payload)
+	if err != nil {
+		return nil, err
+	}
+	return &ReceiveMessage{
+		Source:   w.SourceChainID,
+		Sender:   envelope.Sender,
+		Receiver: envelope.Receiver,
+		Payload:  envelope.Payload,
+	}, nil
+}
+
+func (*ReceiveMessage) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
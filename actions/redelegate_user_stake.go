@@ -0,0 +1,183 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/emission"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+// RedelegateUserStakeComputeUnits costs as much as one DelegateUserStake
+// plus one UndelegateUserStake: the same two DelegateUserStakeKey entries
+// are touched, just atomically instead of across two transactions.
+const RedelegateUserStakeComputeUnits = DelegateUserStakeComputeUnits + UndelegateUserStakeComputeUnits
+
+var (
+	// OutputSameValidator is returned when NewNodeID equals OldNodeID: use
+	// IncreaseDelegatorStake to top up a position, not RedelegateUserStake.
+	OutputSameValidator = []byte("cannot redelegate to the same validator")
+	// OutputRedelegationOnCooldown is returned when actor redelegated more
+	// recently than emission.GetStakingConfig().RedelegationCooldown blocks
+	// ago.
+	OutputRedelegationOnCooldown = []byte("redelegation is on cooldown")
+)
+
+var _ chain.Action = (*RedelegateUserStake)(nil)
+
+// RedelegateUserStake atomically moves a delegation from OldNodeID to
+// NewNodeID: unlike calling UndelegateUserStake followed by
+// DelegateUserStake, the principal never lands back in actor's free balance
+// in between, and the move is subject to
+// emission.GetStakingConfig().RedelegationCooldown rather than being
+// available every block.
+type RedelegateUserStake struct {
+	OldNodeID     []byte        `json:"oldNodeID"`     // Node ID currently delegated to
+	NewNodeID     []byte        `json:"newNodeID"`     // Node ID to move the delegation to
+	StakeEndBlock uint64        `json:"stakeEndBlock"` // Block height at which the new delegation should end
+	RewardAddress codec.Address `json:"rewardAddress"` // Address to receive rewards going forward
+}
+
+func (*RedelegateUserStake) GetTypeID() uint8 {
+	return nconsts.RedelegateUserStakeID
+}
+
+func (r *RedelegateUserStake) StateKeys(actor codec.Address, _ ids.ID) []string {
+	oldNodeID, _ := ids.ToNodeID(r.OldNodeID)
+	newNodeID, _ := ids.ToNodeID(r.NewNodeID)
+	return []string{
+		string(storage.BalanceKey(actor, ids.Empty)),
+		string(storage.DelegateUserStakeKey(actor, oldNodeID)),
+		string(storage.DelegateUserStakeKey(actor, newNodeID)),
+		string(storage.RegisterValidatorStakeKey(oldNodeID)),
+		string(storage.RegisterValidatorStakeKey(newNodeID)),
+		string(storage.RedelegationCooldownKey(actor)),
+	}
+}
+
+func (*RedelegateUserStake) StateKeysMaxChunks() []uint16 {
+	return []uint16{
+		storage.BalanceChunks,
+		storage.DelegateUserStakeChunks,
+		storage.DelegateUserStakeChunks,
+		storage.RegisterValidatorStakeChunks,
+		storage.RegisterValidatorStakeChunks,
+		storage.RedelegationCooldownChunks,
+	}
+}
+
+func (*RedelegateUserStake) OutputsWarpMessage() bool {
+	return false
+}
+
+func (r *RedelegateUserStake) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	oldNodeID, err := ids.ToNodeID(r.OldNodeID)
+	if err != nil {
+		return false, RedelegateUserStakeComputeUnits, OutputInvalidNodeID, nil, nil
+	}
+	newNodeID, err := ids.ToNodeID(r.NewNodeID)
+	if err != nil {
+		return false, RedelegateUserStakeComputeUnits, OutputInvalidNodeID, nil, nil
+	}
+	if oldNodeID == newNodeID {
+		return false, RedelegateUserStakeComputeUnits, OutputSameValidator, nil, nil
+	}
+
+	exists, stakeStartBlock, _, stakedAmount, _, _, autoCompound, _ := storage.GetDelegateUserStake(ctx, mu, actor, oldNodeID)
+	if !exists {
+		return false, RedelegateUserStakeComputeUnits, OutputUserNotStaked, nil, nil
+	}
+	if exists, _, _, _, _, _, _, _ := storage.GetDelegateUserStake(ctx, mu, actor, newNodeID); exists {
+		return false, RedelegateUserStakeComputeUnits, OutputUserAlreadyStaked, nil, nil
+	}
+	if newExists, _, _, _, _, _, _, _ := storage.GetRegisterValidatorStake(ctx, mu, newNodeID); !newExists {
+		return false, RedelegateUserStakeComputeUnits, OutputValidatorNotYetRegistered, nil, nil
+	}
+
+	if r.StakeEndBlock <= stakeStartBlock {
+		return false, RedelegateUserStakeComputeUnits, OutputInvalidStakeEndBlock, nil, nil
+	}
+
+	emissionInstance := emission.GetEmission()
+	stakingConfig := emission.GetStakingConfig()
+	lastRedelegatedAt := storage.GetRedelegationCooldown(ctx, mu, actor)
+	if height := emissionInstance.GetLastAcceptedBlockHeight(); height < lastRedelegatedAt+stakingConfig.RedelegationCooldown {
+		return false, RedelegateUserStakeComputeUnits, OutputRedelegationOnCooldown, nil, nil
+	}
+
+	rewardAmount, err := emissionInstance.RedelegateUserStake(oldNodeID, newNodeID, actor, stakedAmount, r.StakeEndBlock)
+	if err != nil {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	if err := storage.DeleteDelegateUserStake(ctx, mu, actor, oldNodeID); err != nil {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetDelegateUserStake(ctx, mu, actor, newNodeID, emissionInstance.GetLastAcceptedBlockHeight(), r.StakeEndBlock, stakedAmount, r.RewardAddress, autoCompound); err != nil {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetRedelegationCooldown(ctx, mu, actor, emissionInstance.GetLastAcceptedBlockHeight()); err != nil {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if rewardAmount > 0 {
+		if err := storage.AddBalance(ctx, mu, r.RewardAddress, ids.Empty, rewardAmount, true); err != nil {
+			return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+		}
+	}
+
+	sr := &RegisterStakeResult{stakedAmount}
+	output, err := sr.Marshal()
+	if err != nil {
+		return false, RedelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	return true, RedelegateUserStakeComputeUnits, output, nil, nil
+}
+
+func (*RedelegateUserStake) MaxComputeUnits(chain.Rules) uint64 {
+	return RedelegateUserStakeComputeUnits
+}
+
+func (*RedelegateUserStake) Size() int {
+	return 2*hconsts.NodeIDLen + hconsts.Uint64Len + codec.AddressLen
+}
+
+func (r *RedelegateUserStake) Marshal(p *codec.Packer) {
+	p.PackBytes(r.OldNodeID)
+	p.PackBytes(r.NewNodeID)
+	p.PackUint64(r.StakeEndBlock)
+	p.PackAddress(r.RewardAddress)
+}
+
+func UnmarshalRedelegateUserStake(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var redelegate RedelegateUserStake
+	p.UnpackBytes(hconsts.NodeIDLen, true, &redelegate.OldNodeID)
+	p.UnpackBytes(hconsts.NodeIDLen, true, &redelegate.NewNodeID)
+	redelegate.StakeEndBlock = p.UnpackUint64(true)
+	p.UnpackAddress(&redelegate.RewardAddress)
+	return &redelegate, p.Err()
+}
+
+func (*RedelegateUserStake) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
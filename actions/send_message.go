@@ -0,0 +1,125 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+)
+
+// MaxMessagePayloadSize bounds a SendMessage's Payload, keeping the warp
+// message it produces well within AvalancheGo's own warp message size
+// limit regardless of what Receiver does with it.
+const MaxMessagePayloadSize = 2048
+
+// OutputMessageTooLarge is returned when a SendMessage's Payload exceeds
+// MaxMessagePayloadSize.
+var OutputMessageTooLarge = []byte("message payload exceeds max size")
+
+var _ chain.Action = (*SendMessage)(nil)
+
+// SendMessage emits a warp message carrying an opaque Payload addressed to
+// Receiver on Destination: a generic Interchain Messaging (ICM) primitive,
+// independent of ExportAsset/ImportAsset's own warp-based asset bridging
+// rather than something those actions are built on top of. Once relayed,
+// ReceiveMessage dispatches the payload to whatever icm.Receiver is
+// registered for Receiver on Destination, letting a program there react to
+// arbitrary messages instead of only a hard-coded asset import.
+type SendMessage struct {
+	// Destination is the blockchain ID the emitted warp message should be
+	// relayed to.
+	Destination ids.ID `json:"destination"`
+	// Receiver identifies the icm.Receiver registered to handle this message
+	// on Destination, analogous to a contract address.
+	Receiver codec.Address `json:"receiver"`
+	// Payload is opaque to SendMessage/ReceiveMessage; its contents are
+	// defined entirely by Receiver's handler.
+	Payload []byte `json:"payload"`
+}
+
+func (*SendMessage) GetTypeID() uint8 {
+	return nconsts.SendMessageID
+}
+
+func (*SendMessage) StateKeys(_ codec.Address, _ ids.ID) []string {
+	return nil
+}
+
+func (*SendMessage) StateKeysMaxChunks() []uint16 {
+	return nil
+}
+
+func (*SendMessage) OutputsWarpMessage() bool {
+	return true
+}
+
+func (s *SendMessage) Execute(
+	_ context.Context,
+	rules chain.Rules,
+	_ state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	units := sendMessageComputeUnits(len(s.Payload))
+	if len(s.Payload) > MaxMessagePayloadSize {
+		return false, units, OutputMessageTooLarge, nil, nil
+	}
+
+	envelope := &messageEnvelope{Sender: actor, Receiver: s.Receiver, Payload: s.Payload}
+	payload, err := envelope.Marshal()
+	if err != nil {
+		return false, units, utils.ErrBytes(err), nil, nil
+	}
+
+	unsigned, err := warp.NewUnsignedMessage(rules.NetworkID(), rules.ChainID(), payload)
+	if err != nil {
+		return false, units, utils.ErrBytes(err), nil, nil
+	}
+	return true, units, nil, unsigned, nil
+}
+
+func (*SendMessage) MaxComputeUnits(chain.Rules) uint64 {
+	return sendMessageComputeUnits(MaxMessagePayloadSize)
+}
+
+// sendMessageComputeUnits scales with payload size, the same way
+// batchTransferComputeUnits scales with entry count, so a larger message
+// doesn't cost the same as an empty one.
+func sendMessageComputeUnits(payloadLen int) uint64 {
+	return 1 + uint64(payloadLen)/128
+}
+
+func (s *SendMessage) Size() int {
+	return ids.IDLen + codec.AddressLen + hconsts.Uint32Len + len(s.Payload)
+}
+
+func (s *SendMessage) Marshal(p *codec.Packer) {
+	p.PackID(s.Destination)
+	p.PackAddress(s.Receiver)
+	p.PackBytes(s.Payload)
+}
+
+func UnmarshalSendMessage(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var s SendMessage
+	p.UnpackID(true, &s.Destination)
+	p.UnpackAddress(&s.Receiver)
+	p.UnpackBytes(MaxMessagePayloadSize, false, &s.Payload)
+	return &s, p.Err()
+}
+
+func (*SendMessage) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
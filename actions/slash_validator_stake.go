@@ -0,0 +1,271 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	hmath "github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/emission"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+// SlashValidatorStakeComputeUnits is a fixed cost: the BLS verification a
+// double-sign proof requires is bounded (two signatures), so unlike
+// ImportAsset's per-signer warp cost there's nothing to scale with input
+// size.
+const SlashValidatorStakeComputeUnits = 5
+
+var (
+	// OutputInvalidSlashReason is returned when Reason is neither
+	// SlashReasonLiveness nor SlashReasonDoubleSign.
+	OutputInvalidSlashReason = []byte("invalid slash reason")
+)
+
+var _ chain.Action = (*SlashValidatorStake)(nil)
+
+// SlashReason identifies why a validator is being slashed, so emission.Slash
+// can charge the genesis-configured fraction for that specific offense
+// rather than a single penalty for every offense.
+type SlashReason uint8
+
+const (
+	SlashReasonLiveness SlashReason = iota
+	SlashReasonDoubleSign
+)
+
+// SlashValidatorStake penalizes a validator for missing
+// StakingConfig.LivenessThreshold consecutive heartbeat blocks (tracked by
+// emission's ValidatorLiveness map and checked by emission.Slash itself), or
+// for double-signing, proven here by two conflicting block headers the
+// validator's BLS key signed at the same height. Either way the actual
+// bookkeeping — burning a fraction of stakedAmount, slashing delegators, and
+// redirecting the remainder to the emission address — happens in
+// emission.Slash and storage.SlashDelegateUserStake; this action only proves
+// the offense and wires the result into state.
+type SlashValidatorStake struct {
+	NodeID []byte      `json:"nodeID"` // Node ID of the validator being slashed
+	Reason SlashReason `json:"reason"` // liveness or double-sign
+
+	// DoubleSign proves Reason == SlashReasonDoubleSign: two block headers at
+	// the same height, signed by the validator's registered BLS key, that
+	// commit to different block IDs. Left zeroed for a liveness slash.
+	HeightA    uint64 `json:"heightA"`
+	BlockIDA   ids.ID `json:"blockIDA"`
+	SignatureA []byte `json:"signatureA"`
+	HeightB    uint64 `json:"heightB"`
+	BlockIDB   ids.ID `json:"blockIDB"`
+	SignatureB []byte `json:"signatureB"`
+}
+
+func (*SlashValidatorStake) GetTypeID() uint8 {
+	return nconsts.SlashValidatorStakeID
+}
+
+// StateKeys declares the validator's own stake key, plus the
+// emission address's balance key Execute actually credits with the
+// redirected slash amount; the delegator keys storage.SlashDelegateUserStake
+// touches aren't known until Execute looks up nodeID's delegator set, so
+// unlike every other staking action, SlashValidatorStake can't give the
+// parallel-execution engine an exact key set up front and relies on
+// conflicting with DelegateUserStake's own declared keys for correctness
+// instead.
+func (s *SlashValidatorStake) StateKeys(_ codec.Address, _ ids.ID) []string {
+	nodeID, _ := ids.ToNodeID(s.NodeID)
+	emissionAddr, _ := slashEmissionAddress()
+	return []string{
+		string(storage.RegisterValidatorStakeKey(nodeID)),
+		string(storage.BalanceKey(emissionAddr, ids.Empty)),
+	}
+}
+
+// slashEmissionAddress parses stakingConfig.EmissionAddress, the account
+// SlashValidatorStake redirects a slashed validator's (and its delegators')
+// burn to. StateKeys calls it to declare the same balance key Execute
+// actually writes; Execute calls it again and, unlike StateKeys, still
+// checks the error, since a misconfigured genesis should fail the action
+// rather than silently redirect into codec.EmptyAddress.
+func slashEmissionAddress() (codec.Address, error) {
+	return codec.ParseAddressBech32(nconsts.HRP, emission.GetStakingConfig().EmissionAddress)
+}
+
+func (*SlashValidatorStake) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.RegisterValidatorStakeChunks, storage.BalanceChunks}
+}
+
+func (*SlashValidatorStake) OutputsWarpMessage() bool {
+	return false
+}
+
+func (s *SlashValidatorStake) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	_ codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	nodeID, err := ids.ToNodeID(s.NodeID)
+	if err != nil {
+		return false, SlashValidatorStakeComputeUnits, OutputInvalidNodeID, nil, nil
+	}
+
+	exists, blsPublicKey, _, stakedAmount, _, _, _, _ := storage.GetRegisterValidatorStake(ctx, mu, nodeID)
+	if !exists {
+		return false, SlashValidatorStakeComputeUnits, OutputValidatorNotYetRegistered, nil, nil
+	}
+
+	switch s.Reason {
+	case SlashReasonDoubleSign:
+		if err := verifyDoubleSignProof(blsPublicKey, s); err != nil {
+			return false, SlashValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+		}
+	case SlashReasonLiveness:
+		// No proof travels with the action: emission.Slash below consults the
+		// ValidatorLiveness map it already maintains in EndBlock and rejects
+		// the slash itself if nodeID hasn't actually crossed the threshold.
+	default:
+		return false, SlashValidatorStakeComputeUnits, OutputInvalidSlashReason, nil, nil
+	}
+
+	stakingConfig := emission.GetStakingConfig()
+	emissionInstance := emission.GetEmission()
+
+	// Slash in Emission Balancer: validates liveness eligibility for
+	// SlashReasonLiveness, burns stakingConfig.SlashFraction of stakedAmount
+	// from the validator's own stake, jails the validator for
+	// stakingConfig.JailDuration blocks, and returns the amount to redirect
+	// to the emission address.
+	slashAmount, err := emissionInstance.Slash(nodeID, stakingConfig.SlashFraction, uint8(s.Reason))
+	if err != nil {
+		return false, SlashValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	if err := storage.SetRegisterValidatorStakeAmount(ctx, mu, nodeID, stakedAmount-slashAmount); err != nil {
+		return false, SlashValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	delegatorSlashAmount, err := storage.SlashDelegateUserStake(ctx, mu, nodeID, stakingConfig.SlashFraction)
+	if err != nil {
+		return false, SlashValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	emissionAddr, err := slashEmissionAddress()
+	if err != nil {
+		return false, SlashValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	redirected, err := hmath.Add64(slashAmount, delegatorSlashAmount)
+	if err != nil {
+		return false, SlashValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.AddBalance(ctx, mu, emissionAddr, ids.Empty, redirected, true); err != nil {
+		return false, SlashValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	sr := &RegisterStakeResult{redirected}
+	output, err := sr.Marshal()
+	if err != nil {
+		return false, SlashValidatorStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	return true, SlashValidatorStakeComputeUnits, output, nil, nil
+}
+
+// verifyDoubleSignProof checks that HeightA == HeightB, BlockIDA != BlockIDB,
+// and that both signatures verify against blsPublicKeyBytes over their
+// respective (height, blockID) pair — conclusive evidence the same validator
+// signed two different blocks at one height.
+func verifyDoubleSignProof(blsPublicKeyBytes []byte, s *SlashValidatorStake) error {
+	if s.HeightA != s.HeightB {
+		return errors.New("double-sign proof headers are not at the same height")
+	}
+	if s.BlockIDA == s.BlockIDB {
+		return errors.New("double-sign proof headers commit to the same block")
+	}
+
+	pk, err := bls.PublicKeyFromCompressedBytes(blsPublicKeyBytes)
+	if err != nil {
+		return err
+	}
+	sigA, err := bls.SignatureFromBytes(s.SignatureA)
+	if err != nil {
+		return err
+	}
+	sigB, err := bls.SignatureFromBytes(s.SignatureB)
+	if err != nil {
+		return err
+	}
+
+	if !bls.Verify(pk, sigA, doubleSignMessage(s.HeightA, s.BlockIDA)) {
+		return errors.New("double-sign proof signature A does not verify against the validator's BLS key")
+	}
+	if !bls.Verify(pk, sigB, doubleSignMessage(s.HeightB, s.BlockIDB)) {
+		return errors.New("double-sign proof signature B does not verify against the validator's BLS key")
+	}
+	return nil
+}
+
+// doubleSignMessage is the byte string a validator's BLS key signs over to
+// attest to a block: the height followed by the block ID, matching the
+// layout every conflicting-header signature in a SlashValidatorStake proof
+// must cover.
+func doubleSignMessage(height uint64, blockID ids.ID) []byte {
+	var buf bytes.Buffer
+	var heightBytes [hconsts.Uint64Len]byte
+	binary.BigEndian.PutUint64(heightBytes[:], height)
+	buf.Write(heightBytes[:])
+	buf.Write(blockID[:])
+	return buf.Bytes()
+}
+
+func (*SlashValidatorStake) MaxComputeUnits(chain.Rules) uint64 {
+	return SlashValidatorStakeComputeUnits
+}
+
+func (*SlashValidatorStake) Size() int {
+	return hconsts.NodeIDLen + hconsts.Uint8Len + 2*hconsts.Uint64Len + 2*hconsts.IDLen + 2*bls.SignatureLen
+}
+
+func (s *SlashValidatorStake) Marshal(p *codec.Packer) {
+	p.PackBytes(s.NodeID)
+	p.PackByte(uint8(s.Reason))
+	p.PackUint64(s.HeightA)
+	p.PackID(s.BlockIDA)
+	p.PackBytes(s.SignatureA)
+	p.PackUint64(s.HeightB)
+	p.PackID(s.BlockIDB)
+	p.PackBytes(s.SignatureB)
+}
+
+func UnmarshalSlashValidatorStake(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var slash SlashValidatorStake
+	p.UnpackBytes(hconsts.NodeIDLen, true, &slash.NodeID)
+	slash.Reason = SlashReason(p.UnpackByte())
+	slash.HeightA = p.UnpackUint64(false)
+	p.UnpackID(false, &slash.BlockIDA)
+	p.UnpackBytes(bls.SignatureLen, false, &slash.SignatureA)
+	slash.HeightB = p.UnpackUint64(false)
+	p.UnpackID(false, &slash.BlockIDB)
+	p.UnpackBytes(bls.SignatureLen, false, &slash.SignatureB)
+	return &slash, p.Err()
+}
+
+func (*SlashValidatorStake) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
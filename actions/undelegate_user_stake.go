@@ -0,0 +1,126 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/emission"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+var _ chain.Action = (*UndelegateUserStake)(nil)
+
+type UndelegateUserStake struct {
+	NodeID []byte `json:"nodeID"` // Node ID of the validator to undelegate from
+}
+
+func (*UndelegateUserStake) GetTypeID() uint8 {
+	return nconsts.UndelegateUserStakeID
+}
+
+func (u *UndelegateUserStake) StateKeys(actor codec.Address, _ ids.ID) []string {
+	nodeID, _ := ids.ToNodeID(u.NodeID)
+	return []string{
+		string(storage.BalanceKey(actor, ids.Empty)),
+		string(storage.DelegateUserStakeKey(actor, nodeID)),
+		string(storage.RegisterValidatorStakeKey(nodeID)),
+	}
+}
+
+func (*UndelegateUserStake) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.BalanceChunks, storage.DelegateUserStakeChunks, storage.RegisterValidatorStakeChunks}
+}
+
+func (*UndelegateUserStake) OutputsWarpMessage() bool {
+	return false
+}
+
+func (u *UndelegateUserStake) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	nodeID, err := ids.ToNodeID(u.NodeID)
+	if err != nil {
+		return false, UndelegateUserStakeComputeUnits, OutputInvalidNodeID, nil, nil
+	}
+
+	// Check if the user has an active delegation to this validator node
+	exists, _, stakeEndBlock, stakedAmount, rewardAddress, _, _, _ := storage.GetDelegateUserStake(ctx, mu, actor, nodeID)
+	if !exists {
+		return false, UndelegateUserStakeComputeUnits, OutputUserNotStaked, nil, nil
+	}
+
+	// Get the emission instance
+	emissionInstance := emission.GetEmission()
+
+	// Gate on the last-accepted block height, the same deterministic
+	// quantity DelegateUserStake validates StakeStartBlock against, rather
+	// than any wall-clock reading: every validator must reach this check
+	// with an identical result.
+	if emissionInstance.GetLastAcceptedBlockHeight() < stakeEndBlock {
+		return false, UndelegateUserStakeComputeUnits, OutputStakeNotStarted, nil, nil
+	}
+
+	// Undelegate in Emission Balancer
+	rewardAmount, err := emissionInstance.UndelegateUserStake(nodeID, actor)
+	if err != nil {
+		return false, UndelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	if err := storage.AddBalance(ctx, mu, rewardAddress, ids.Empty, rewardAmount, true); err != nil {
+		return false, UndelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.DeleteDelegateUserStake(ctx, mu, actor, nodeID); err != nil {
+		return false, UndelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.AddBalance(ctx, mu, actor, ids.Empty, stakedAmount, true); err != nil {
+		return false, UndelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	sr := &RegisterStakeResult{stakedAmount}
+	output, err := sr.Marshal()
+	if err != nil {
+		return false, UndelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	return true, UndelegateUserStakeComputeUnits, output, nil, nil
+}
+
+func (*UndelegateUserStake) MaxComputeUnits(chain.Rules) uint64 {
+	return UndelegateUserStakeComputeUnits
+}
+
+func (*UndelegateUserStake) Size() int {
+	return hconsts.NodeIDLen
+}
+
+func (u *UndelegateUserStake) Marshal(p *codec.Packer) {
+	p.PackBytes(u.NodeID)
+}
+
+func UnmarshalUndelegateUserStake(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var unstake UndelegateUserStake
+	p.UnpackBytes(hconsts.NodeIDLen, true, &unstake.NodeID)
+	return &unstake, p.Err()
+}
+
+func (*UndelegateUserStake) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+)
+
+// warpTransfer is the wire format ExportAsset packs into the warp message it
+// emits, and ImportAsset unpacks from the verified warp message it consumes.
+// It carries everything ImportAsset needs to land the transfer without
+// trusting anything the importer supplies directly, the same way
+// messageEnvelope carries SendMessage/ReceiveMessage's fields. Return and
+// the swap fields carry a single uint64 "0 means unset/false" the same way
+// every other field here does, rather than introducing a bool wire type.
+type warpTransfer struct {
+	// To is the address ImportAsset credits on the destination chain.
+	To codec.Address
+	// Asset is always the asset's native identity: for a forward transfer
+	// (Return false) this is the asset as ExportAsset's own chain knows it
+	// (ids.Empty for the native asset); for a return transfer (Return true)
+	// ExportAsset recovers this from the asset being returned's
+	// ImportedAssetMetadata before emitting the warp message, so ImportAsset
+	// never has to unwrap anything itself.
+	Asset ids.ID
+	// Value is the amount of Asset being transferred, excluding Reward.
+	Value uint64
+	// Return marks this transfer as unwinding an earlier forward transfer:
+	// ImportAsset decrements the outstanding loan instead of minting a new
+	// imported asset.
+	Return bool
+	// Reward is paid out alongside Value when ImportAsset lands the
+	// transfer; it exists to incentivize someone to submit the return leg
+	// of a transfer promptly.
+	Reward uint64
+	// TxID is the ExportAsset transaction that produced this transfer, so
+	// ImportAsset can record it as already delivered and reject a replay of
+	// the same warp message.
+	TxID ids.ID
+
+	// SwapIn, AssetOut, SwapOut, and SwapExpiry describe an optional
+	// atomic swap order attached to the transfer: if ImportAsset is
+	// submitted with Fill set before SwapExpiry, its actor pays SwapOut of
+	// AssetOut to To in exchange for SwapIn of the imported Value, instead
+	// of the entire Value landing on To alone. SwapIn == 0 means no order
+	// is attached.
+	SwapIn     uint64
+	AssetOut   ids.ID
+	SwapOut    uint64
+	SwapExpiry int64
+}
+
+func (*warpTransfer) size() int {
+	return codec.AddressLen + ids.IDLen + hconsts.Uint64Len + hconsts.Uint64Len + hconsts.Uint64Len + ids.IDLen +
+		hconsts.Uint64Len + ids.IDLen + hconsts.Uint64Len + hconsts.Uint64Len
+}
+
+func (t *warpTransfer) Marshal() ([]byte, error) {
+	size := t.size()
+	p := codec.NewWriter(size, size)
+	p.PackAddress(t.To)
+	p.PackID(t.Asset)
+	p.PackUint64(t.Value)
+	p.PackUint64(boolToUint64(t.Return))
+	p.PackUint64(t.Reward)
+	p.PackID(t.TxID)
+	p.PackUint64(t.SwapIn)
+	p.PackID(t.AssetOut)
+	p.PackUint64(t.SwapOut)
+	p.PackUint64(uint64(t.SwapExpiry))
+	return p.Bytes(), p.Err()
+}
+
+func unmarshalWarpTransfer(b []byte) (*warpTransfer, error) {
+	var t warpTransfer
+	p := codec.NewReader(b, t.size())
+	p.UnpackAddress(&t.To)
+	p.UnpackID(false, &t.Asset)
+	t.Value = p.UnpackUint64(false)
+	t.Return = p.UnpackUint64(false) != 0
+	t.Reward = p.UnpackUint64(false)
+	p.UnpackID(false, &t.TxID)
+	t.SwapIn = p.UnpackUint64(false)
+	p.UnpackID(false, &t.AssetOut)
+	t.SwapOut = p.UnpackUint64(false)
+	t.SwapExpiry = int64(p.UnpackUint64(false))
+	return &t, p.Err()
+}
+
+// hasSwap reports whether t carries an atomic swap order for ImportAsset to
+// optionally fill.
+func (t *warpTransfer) hasSwap() bool {
+	return t.SwapIn > 0
+}
+
+func boolToUint64(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -51,7 +51,7 @@ func (u *WithdrawValidatorStake) Execute(
 	ctx context.Context,
 	_ chain.Rules,
 	mu state.Mutable,
-	_ int64,
+	timestamp int64,
 	actor codec.Address,
 	_ ids.ID,
 	_ bool,
@@ -74,14 +74,13 @@ func (u *WithdrawValidatorStake) Execute(
 	// Get the emission instance
 	emissionInstance := emission.GetEmission()
 
-	// Get current time
-	currentTime := time.Now().UTC()
-	// Get last accepted block time
-	lastBlockTime := emissionInstance.GetLastAcceptedBlockTimestamp()
-	// Convert Unix timestamps to Go's time.Time for easier manipulation
+	// Gate on the block timestamp every validator agrees on as part of
+	// consensus, not wall-clock time: time.Now() here would let validators
+	// reach different results for a withdrawal submitted right at
+	// stakeEndTime, which is a consensus divergence waiting to happen.
+	blockTime := time.UnixMilli(timestamp).UTC()
 	endTime := time.Unix(int64(stakeEndTime), 0).UTC()
-	// Check that currentTime and lastBlockTime are after stakeStartTime
-	if currentTime.Before(endTime) || lastBlockTime.Before(endTime) {
+	if blockTime.Before(endTime) {
 		return false, WithdrawValidatorStakeComputeUnits, OutputStakeNotStarted, nil, nil
 	}
 
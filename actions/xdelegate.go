@@ -0,0 +1,403 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Remote delegation deliberately does not reuse ExportAsset/ImportAsset's
+// warp path (see relayer.Relayer): staking a validator's trust is a
+// narrower, longer-lived commitment than a single asset transfer, and
+// hypersdk is moving away from baking AWM into the action interface itself.
+// Instead, a source chain's own validator committee BLS-signs an attestation
+// over the delegation/withdrawal it wants mirrored here, storage.
+// RemoteCommitteeKey records that committee's aggregate public key per
+// SourceChainID (seeded from Genesis.RemoteCommittees), and a per-delegator
+// nonce in storage.RemoteNonceKey rejects replays of an already-applied
+// attestation. relayer.StakeBridge is the off-chain daemon that watches a
+// source chain's delegation event log and submits the two actions below.
+//
+// The two actions still satisfy chain.Action's existing Execute/Unmarshal
+// shape (the *warp.UnsignedMessage return and *warp.Message parameter,
+// always nil/unused here) rather than the warp-free signature a future
+// hypersdk might expose: every action in this registry shares one
+// codec.TypeParser, so only a hypersdk upgrade — not a single nuklaivm
+// chunk — can actually drop warp from that shared interface. What's
+// genuinely new is that, unlike ExportAsset/ImportAsset, neither action
+// here ever builds or verifies a warp.Message; BLS verification against the
+// committee key is the entire proof.
+package actions
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/emission"
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+// RemoteDelegateUserStakeComputeUnits and RemoteWithdrawUserStakeComputeUnits
+// are a fixed cost plus the base DelegateUserStake/UndelegateUserStake
+// price: verifying one aggregate BLS signature costs about as much as
+// verifying one of ImportAsset's warp signers.
+const (
+	RemoteDelegateUserStakeComputeUnits  = DelegateUserStakeComputeUnits + 5
+	RemoteWithdrawUserStakeComputeUnits  = UndelegateUserStakeComputeUnits + 5
+)
+
+var (
+	// OutputUnknownSourceChain is returned when no committee is registered
+	// for a RemoteDelegateUserStake/RemoteWithdrawUserStake's SourceChainID.
+	OutputUnknownSourceChain = []byte("no committee registered for source chain")
+	// OutputRemoteNonceAlreadyUsed is returned when Nonce doesn't match the
+	// delegator's next expected nonce, most commonly because the attestation
+	// was already applied once before.
+	OutputRemoteNonceAlreadyUsed = []byte("remote delegation nonce already used")
+	// OutputInvalidRemoteAttestation is returned when Signature does not
+	// verify against the source chain's registered committee public key.
+	OutputInvalidRemoteAttestation = []byte("remote attestation failed BLS verification")
+)
+
+var _ chain.Action = (*RemoteDelegateUserStake)(nil)
+
+// RemoteDelegateUserStake mirrors a delegation that actually happened on
+// SourceChainID onto this chain's validator set, on the strength of
+// SourceChainID's own committee attesting to it rather than a local balance
+// debit: the staked value is already locked on the source chain, so unlike
+// DelegateUserStake this never calls storage.SubBalance.
+type RemoteDelegateUserStake struct {
+	SourceChainID   ids.ID        `json:"sourceChainID"`   // chain the delegator's stake originates from
+	NodeID          []byte        `json:"nodeID"`          // nuklaivm validator node to delegate to
+	Delegator       codec.Address `json:"delegator"`       // address on this chain credited with the delegation record
+	StakeStartBlock uint64        `json:"stakeStartBlock"` // block height at which the stake should be made
+	StakeEndBlock   uint64        `json:"stakeEndBlock"`   // block height at which the stake should end
+	StakedAmount    uint64        `json:"stakedAmount"`    // amount of NAI-equivalent staked on SourceChainID
+	RewardAddress   codec.Address `json:"rewardAddress"`   // address to receive rewards
+	Nonce           uint64        `json:"nonce"`            // must equal storage.GetRemoteNonce(SourceChainID, Delegator)
+	Signature       []byte        `json:"signature"`        // SourceChainID committee's BLS signature over remoteDelegateMessage
+}
+
+func (*RemoteDelegateUserStake) GetTypeID() uint8 {
+	return nconsts.RemoteDelegateUserStakeID
+}
+
+func (r *RemoteDelegateUserStake) StateKeys(_ codec.Address, _ ids.ID) []string {
+	nodeID, _ := ids.ToNodeID(r.NodeID)
+	return []string{
+		string(storage.RemoteCommitteeKey(r.SourceChainID)),
+		string(storage.RemoteNonceKey(r.SourceChainID, r.Delegator)),
+		string(storage.DelegateUserStakeKey(r.Delegator, nodeID)),
+		string(storage.RegisterValidatorStakeKey(nodeID)),
+	}
+}
+
+func (*RemoteDelegateUserStake) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.RemoteCommitteeChunks, storage.RemoteNonceChunks, storage.DelegateUserStakeChunks, storage.RegisterValidatorStakeChunks}
+}
+
+func (*RemoteDelegateUserStake) OutputsWarpMessage() bool {
+	return false
+}
+
+func (r *RemoteDelegateUserStake) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	_ codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	nodeID, err := ids.ToNodeID(r.NodeID)
+	if err != nil {
+		return false, RemoteDelegateUserStakeComputeUnits, OutputInvalidNodeID, nil, nil
+	}
+
+	exists, _, _, _, _, _, _, _ := storage.GetRegisterValidatorStake(ctx, mu, nodeID)
+	if !exists {
+		return false, RemoteDelegateUserStakeComputeUnits, OutputValidatorNotYetRegistered, nil, nil
+	}
+
+	if output, err := verifyRemoteAttestation(
+		ctx, mu, r.SourceChainID, r.Delegator, r.Nonce, r.Signature, remoteDelegateMessage(r),
+	); err != nil {
+		return false, RemoteDelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	} else if output != nil {
+		return false, RemoteDelegateUserStakeComputeUnits, output, nil, nil
+	}
+
+	if exists, _, _, _, _, _, _, _ := storage.GetDelegateUserStake(ctx, mu, r.Delegator, nodeID); exists {
+		return false, RemoteDelegateUserStakeComputeUnits, OutputUserAlreadyStaked, nil, nil
+	}
+
+	stakingConfig := emission.GetStakingConfig()
+	if r.StakedAmount < stakingConfig.MinDelegatorStake {
+		return false, RemoteDelegateUserStakeComputeUnits, OutputDelegateStakedAmountInvalid, nil, nil
+	}
+	if r.StakeEndBlock <= r.StakeStartBlock {
+		return false, RemoteDelegateUserStakeComputeUnits, OutputInvalidStakeEndBlock, nil, nil
+	}
+
+	emissionInstance := emission.GetEmission()
+	if r.StakeStartBlock < emissionInstance.GetLastAcceptedBlockHeight() {
+		return false, RemoteDelegateUserStakeComputeUnits, OutputInvalidStakeStartBlock, nil, nil
+	}
+
+	if err := emissionInstance.DelegateUserStake(nodeID, r.Delegator, r.StakeStartBlock, r.StakeEndBlock, r.StakedAmount); err != nil {
+		return false, RemoteDelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	// Remote delegations don't carry an AutoCompound flag of their own yet;
+	// they always land as plain, manually-claimed positions.
+	if err := storage.SetDelegateUserStake(ctx, mu, r.Delegator, nodeID, r.StakeStartBlock, r.StakeEndBlock, r.StakedAmount, r.RewardAddress, false); err != nil {
+		return false, RemoteDelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.SetRemoteNonce(ctx, mu, r.SourceChainID, r.Delegator, r.Nonce+1); err != nil {
+		return false, RemoteDelegateUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	return true, RemoteDelegateUserStakeComputeUnits, nil, nil, nil
+}
+
+func (*RemoteDelegateUserStake) MaxComputeUnits(chain.Rules) uint64 {
+	return RemoteDelegateUserStakeComputeUnits
+}
+
+func (*RemoteDelegateUserStake) Size() int {
+	return hconsts.IDLen + hconsts.NodeIDLen + 2*codec.AddressLen + 4*hconsts.Uint64Len + int(bls.SignatureLen)
+}
+
+func (r *RemoteDelegateUserStake) Marshal(p *codec.Packer) {
+	p.PackID(r.SourceChainID)
+	p.PackBytes(r.NodeID)
+	p.PackAddress(r.Delegator)
+	p.PackUint64(r.StakeStartBlock)
+	p.PackUint64(r.StakeEndBlock)
+	p.PackUint64(r.StakedAmount)
+	p.PackAddress(r.RewardAddress)
+	p.PackUint64(r.Nonce)
+	p.PackBytes(r.Signature)
+}
+
+func UnmarshalRemoteDelegateUserStake(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var r RemoteDelegateUserStake
+	p.UnpackID(true, &r.SourceChainID)
+	p.UnpackBytes(hconsts.NodeIDLen, true, &r.NodeID)
+	p.UnpackAddress(&r.Delegator)
+	r.StakeStartBlock = p.UnpackUint64(true)
+	r.StakeEndBlock = p.UnpackUint64(true)
+	r.StakedAmount = p.UnpackUint64(true)
+	p.UnpackAddress(&r.RewardAddress)
+	r.Nonce = p.UnpackUint64(false)
+	p.UnpackBytes(int(bls.SignatureLen), true, &r.Signature)
+	return &r, p.Err()
+}
+
+func (*RemoteDelegateUserStake) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ chain.Action = (*RemoteWithdrawUserStake)(nil)
+
+// RemoteWithdrawUserStake mirrors an undelegation attested to by
+// SourceChainID's committee, crediting RewardAddress and releasing the
+// delegation record the same way UndelegateUserStake does locally.
+type RemoteWithdrawUserStake struct {
+	SourceChainID ids.ID        `json:"sourceChainID"` // chain the original RemoteDelegateUserStake came from
+	NodeID        []byte        `json:"nodeID"`         // validator node being undelegated from
+	Delegator     codec.Address `json:"delegator"`       // address the matching RemoteDelegateUserStake credited
+	Nonce         uint64        `json:"nonce"`           // must equal storage.GetRemoteNonce(SourceChainID, Delegator)
+	Signature     []byte        `json:"signature"`       // SourceChainID committee's BLS signature over remoteWithdrawMessage
+}
+
+func (*RemoteWithdrawUserStake) GetTypeID() uint8 {
+	return nconsts.RemoteWithdrawUserStakeID
+}
+
+func (r *RemoteWithdrawUserStake) StateKeys(_ codec.Address, _ ids.ID) []string {
+	nodeID, _ := ids.ToNodeID(r.NodeID)
+	return []string{
+		string(storage.RemoteCommitteeKey(r.SourceChainID)),
+		string(storage.RemoteNonceKey(r.SourceChainID, r.Delegator)),
+		string(storage.DelegateUserStakeKey(r.Delegator, nodeID)),
+		string(storage.BalanceKey(r.Delegator, ids.Empty)),
+	}
+}
+
+func (*RemoteWithdrawUserStake) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.RemoteCommitteeChunks, storage.RemoteNonceChunks, storage.DelegateUserStakeChunks, storage.BalanceChunks}
+}
+
+func (*RemoteWithdrawUserStake) OutputsWarpMessage() bool {
+	return false
+}
+
+func (r *RemoteWithdrawUserStake) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	_ codec.Address,
+	_ ids.ID,
+	_ bool,
+) (bool, uint64, []byte, *warp.UnsignedMessage, error) {
+	nodeID, err := ids.ToNodeID(r.NodeID)
+	if err != nil {
+		return false, RemoteWithdrawUserStakeComputeUnits, OutputInvalidNodeID, nil, nil
+	}
+
+	exists, _, stakeEndBlock, stakedAmount, rewardAddress, _, _, _ := storage.GetDelegateUserStake(ctx, mu, r.Delegator, nodeID)
+	if !exists {
+		return false, RemoteWithdrawUserStakeComputeUnits, OutputUserNotStaked, nil, nil
+	}
+
+	if output, err := verifyRemoteAttestation(
+		ctx, mu, r.SourceChainID, r.Delegator, r.Nonce, r.Signature, remoteWithdrawMessage(r),
+	); err != nil {
+		return false, RemoteWithdrawUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	} else if output != nil {
+		return false, RemoteWithdrawUserStakeComputeUnits, output, nil, nil
+	}
+
+	emissionInstance := emission.GetEmission()
+	if emissionInstance.GetLastAcceptedBlockHeight() < stakeEndBlock {
+		return false, RemoteWithdrawUserStakeComputeUnits, OutputStakeNotStarted, nil, nil
+	}
+
+	rewardAmount, err := emissionInstance.UndelegateUserStake(nodeID, r.Delegator)
+	if err != nil {
+		return false, RemoteWithdrawUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	if err := storage.AddBalance(ctx, mu, rewardAddress, ids.Empty, rewardAmount, true); err != nil {
+		return false, RemoteWithdrawUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	if err := storage.DeleteDelegateUserStake(ctx, mu, r.Delegator, nodeID); err != nil {
+		return false, RemoteWithdrawUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+	// Unlike UndelegateUserStake, stakedAmount isn't credited to a local
+	// balance: it was never debited from one, since it originated on
+	// SourceChainID. The source chain is responsible for releasing it there
+	// once it observes this withdrawal land.
+	if err := storage.SetRemoteNonce(ctx, mu, r.SourceChainID, r.Delegator, r.Nonce+1); err != nil {
+		return false, RemoteWithdrawUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	sr := &RegisterStakeResult{stakedAmount}
+	output, err := sr.Marshal()
+	if err != nil {
+		return false, RemoteWithdrawUserStakeComputeUnits, utils.ErrBytes(err), nil, nil
+	}
+
+	return true, RemoteWithdrawUserStakeComputeUnits, output, nil, nil
+}
+
+func (*RemoteWithdrawUserStake) MaxComputeUnits(chain.Rules) uint64 {
+	return RemoteWithdrawUserStakeComputeUnits
+}
+
+func (*RemoteWithdrawUserStake) Size() int {
+	return hconsts.IDLen + hconsts.NodeIDLen + codec.AddressLen + hconsts.Uint64Len + int(bls.SignatureLen)
+}
+
+func (r *RemoteWithdrawUserStake) Marshal(p *codec.Packer) {
+	p.PackID(r.SourceChainID)
+	p.PackBytes(r.NodeID)
+	p.PackAddress(r.Delegator)
+	p.PackUint64(r.Nonce)
+	p.PackBytes(r.Signature)
+}
+
+func UnmarshalRemoteWithdrawUserStake(p *codec.Packer, _ *warp.Message) (chain.Action, error) {
+	var r RemoteWithdrawUserStake
+	p.UnpackID(true, &r.SourceChainID)
+	p.UnpackBytes(hconsts.NodeIDLen, true, &r.NodeID)
+	p.UnpackAddress(&r.Delegator)
+	r.Nonce = p.UnpackUint64(false)
+	p.UnpackBytes(int(bls.SignatureLen), true, &r.Signature)
+	return &r, p.Err()
+}
+
+func (*RemoteWithdrawUserStake) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+// verifyRemoteAttestation checks Nonce against the delegator's next expected
+// nonce and sig against SourceChainID's registered committee public key over
+// msg, the two checks every remote staking action needs before touching any
+// stake bookkeeping. A non-nil []byte return is one of this file's OutputXxx
+// constants and should be returned as the action's output as-is; a non-nil
+// error is an unexpected failure (a malformed key or signature) that belongs
+// behind utils.ErrBytes, same as every other action in this package.
+func verifyRemoteAttestation(
+	ctx context.Context,
+	mu state.Mutable,
+	sourceChainID ids.ID,
+	delegator codec.Address,
+	nonce uint64,
+	sig []byte,
+	msg []byte,
+) ([]byte, error) {
+	committeePublicKey, exists := storage.GetRemoteCommittee(ctx, mu, sourceChainID)
+	if !exists {
+		return OutputUnknownSourceChain, nil
+	}
+	expected := storage.GetRemoteNonce(ctx, mu, sourceChainID, delegator)
+	if nonce != expected {
+		return OutputRemoteNonceAlreadyUsed, nil
+	}
+
+	pk, err := bls.PublicKeyFromCompressedBytes(committeePublicKey)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := bls.SignatureFromBytes(sig)
+	if err != nil {
+		return nil, err
+	}
+	if !bls.Verify(pk, signature, msg) {
+		return OutputInvalidRemoteAttestation, nil
+	}
+	return nil, nil
+}
+
+// remoteDelegateMessage is the canonical byte string SourceChainID's
+// committee signs to attest to a delegation: every field the delegation
+// itself is keyed or gated on, in wire order, so two different delegations
+// (or a tampered field) never hash to the same message.
+func remoteDelegateMessage(r *RemoteDelegateUserStake) []byte {
+	var buf bytes.Buffer
+	buf.Write(r.SourceChainID[:])
+	buf.Write(r.NodeID)
+	buf.Write(r.Delegator[:])
+	var nums [3 * hconsts.Uint64Len]byte
+	binary.BigEndian.PutUint64(nums[0:8], r.StakeStartBlock)
+	binary.BigEndian.PutUint64(nums[8:16], r.StakeEndBlock)
+	binary.BigEndian.PutUint64(nums[16:24], r.StakedAmount)
+	buf.Write(nums[:])
+	buf.Write(r.RewardAddress[:])
+	var nonce [hconsts.Uint64Len]byte
+	binary.BigEndian.PutUint64(nonce[:], r.Nonce)
+	buf.Write(nonce[:])
+	return buf.Bytes()
+}
+
+// remoteWithdrawMessage is remoteDelegateMessage's withdrawal counterpart.
+func remoteWithdrawMessage(r *RemoteWithdrawUserStake) []byte {
+	var buf bytes.Buffer
+	buf.Write(r.SourceChainID[:])
+	buf.Write(r.NodeID)
+	buf.Write(r.Delegator[:])
+	var nonce [hconsts.Uint64Len]byte
+	binary.BigEndian.PutUint64(nonce[:], r.Nonce)
+	buf.Write(nonce[:])
+	return buf.Bytes()
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package challenge implements the proof-of-work puzzles the faucet hands
+// out before airdropping funds. Callers pick a Scheme by SchemeID so the
+// cost of solving can be tuned independently of the hash-prefix default
+// (e.g. to raise the bar against GPU/ASIC farming) without changing how the
+// faucet itself issues and verifies challenges.
+package challenge
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// SchemeID identifies a challenge algorithm. It is sent alongside salt and
+// difficulty so solvers (and SolveChallenge) know which Scheme to use.
+type SchemeID uint8
+
+const (
+	// HashPrefix is the original scheme: find a solution whose salted hash has
+	// [difficulty] leading zero bits. Cheap to verify, fast on CPU and GPU
+	// alike.
+	HashPrefix SchemeID = iota
+	// EquihashLike is a memory-hard scheme modeled on Equihash: solving
+	// requires materializing a large working set, which narrows the gap
+	// between CPU and ASIC/GPU solvers.
+	EquihashLike
+	// VDF is a verifiable-delay-function scheme: solving requires a fixed
+	// number of strictly sequential steps that cannot be parallelized, so
+	// extra hardware doesn't help solve it any faster.
+	VDF
+
+	saltLen = 32
+)
+
+// Scheme is a pluggable challenge algorithm.
+type Scheme interface {
+	ID() SchemeID
+	// New generates a fresh salt for this scheme.
+	New() ([]byte, error)
+	// Verify reports whether solution is a valid answer to salt at the given
+	// difficulty.
+	Verify(salt, solution []byte, difficulty uint16) bool
+}
+
+var schemes = map[SchemeID]Scheme{
+	HashPrefix:   hashPrefixScheme{},
+	EquihashLike: equihashScheme{},
+	VDF:          vdfScheme{},
+}
+
+// Get returns the registered Scheme for [id].
+func Get(id SchemeID) (Scheme, error) {
+	s, ok := schemes[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown challenge scheme %d", id)
+	}
+	return s, nil
+}
+
+func randomSalt() ([]byte, error) {
+	salt := make([]byte, saltLen)
+	_, err := rand.Read(salt)
+	return salt, err
+}
+
+// New generates a new hash-prefix salt. Kept as a package-level function (in
+// addition to the HashPrefix scheme's own New) so existing single-scheme callers don't need
+// to change.
+func New() ([]byte, error) {
+	return hashPrefixScheme{}.New()
+}
+
+// Verify checks a solution against the hash-prefix scheme, matching the
+// faucet's original (pre-Scheme) behavior.
+func Verify(salt, solution []byte, difficulty uint16) bool {
+	return hashPrefixScheme{}.Verify(salt, solution, difficulty)
+}
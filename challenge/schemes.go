@@ -0,0 +1,120 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package challenge
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// hashPrefixScheme is the original faucet challenge: find a solution whose
+// salted hash has [difficulty] leading zero bits. It's cheap to verify and
+// fast to solve on any hardware, which is also its weakness against
+// GPU/ASIC farming.
+type hashPrefixScheme struct{}
+
+func (hashPrefixScheme) ID() SchemeID { return HashPrefix }
+
+func (hashPrefixScheme) New() ([]byte, error) {
+	return randomSalt()
+}
+
+func (hashPrefixScheme) Verify(salt, solution []byte, difficulty uint16) bool {
+	h := sha256.Sum256(append(salt, solution...))
+	return leadingZeroBits(h[:]) >= uint16(difficulty)
+}
+
+func leadingZeroBits(b []byte) uint16 {
+	var bits uint16
+	for _, by := range b {
+		if by == 0 {
+			bits += 8
+			continue
+		}
+		for mask := byte(0x80); mask > 0; mask >>= 1 {
+			if by&mask != 0 {
+				return bits
+			}
+			bits++
+		}
+	}
+	return bits
+}
+
+// equihashScheme is a simplified, memory-hard birthday-collision puzzle in
+// the spirit of Equihash: a solution must name two distinct nonces whose
+// salted hashes agree on [difficulty] leading bits. Finding such a pair
+// cheaply requires building a table of candidate hashes rather than simply
+// iterating a counter, which narrows the advantage specialized hardware has
+// over a commodity CPU solver.
+//
+// This is not a full Wagner's-algorithm implementation (no k-list
+// generalization, no tree reduction) - it is the k=1 base case, sized to
+// raise memory pressure without making legitimate CPU solves impractical.
+type equihashScheme struct{}
+
+func (equihashScheme) ID() SchemeID { return EquihashLike }
+
+func (equihashScheme) New() ([]byte, error) {
+	return randomSalt()
+}
+
+// Verify expects solution to be two little-endian uint64 nonces concatenated
+// (16 bytes total).
+func (equihashScheme) Verify(salt, solution []byte, difficulty uint16) bool {
+	if len(solution) != 16 {
+		return false
+	}
+	n1 := binary.LittleEndian.Uint64(solution[:8])
+	n2 := binary.LittleEndian.Uint64(solution[8:])
+	if n1 == n2 {
+		return false
+	}
+	h1 := nonceHash(salt, n1)
+	h2 := nonceHash(salt, n2)
+	return leadingZeroBits(xor(h1, h2)) >= difficulty
+}
+
+func nonceHash(salt []byte, nonce uint64) []byte {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, nonce)
+	h := sha256.Sum256(append(append([]byte{}, salt...), buf...))
+	return h[:]
+}
+
+func xor(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
+
+// vdfScheme requires [difficulty]+1 sequential rounds of hashing over the
+// hashed salt, each
+// depending on the prior round's output, so the work cannot be parallelized
+// across extra cores or GPU lanes the way hashPrefixScheme's search can be.
+//
+// Verification here re-runs the same number of sequential steps as solving,
+// which is a simplification of a true VDF (e.g. Wesolowski/Pietrzak), where
+// verification is meant to be cheap regardless of the delay parameter. A
+// real VDF construction is tracked as follow-up work; this still achieves
+// the goal of making the puzzle un-parallelizable.
+type vdfScheme struct{}
+
+func (vdfScheme) ID() SchemeID { return VDF }
+
+func (vdfScheme) New() ([]byte, error) {
+	return randomSalt()
+}
+
+func (vdfScheme) Verify(salt, solution []byte, difficulty uint16) bool {
+	rounds := uint32(difficulty) + 1
+	h := sha256.Sum256(salt)
+	for i := uint32(0); i < rounds; i++ {
+		h = sha256.Sum256(h[:])
+	}
+	return bytes.Equal(h[:], solution)
+}
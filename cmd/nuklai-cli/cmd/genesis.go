@@ -0,0 +1,22 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var genesisCmd = &cobra.Command{
+	Use: "genesis",
+	RunE: func(*cobra.Command, []string) error {
+		return ErrMissingSubcommand
+	},
+}
+
+var genesisUpgradesCmd = &cobra.Command{
+	Use: "upgrades",
+	RunE: func(_ *cobra.Command, args []string) error {
+		return handler.Root().PrintPendingUpgrades()
+	},
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package manager
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType identifies the kind of operational event emitted on the faucet's
+// `/events` WebSocket stream.
+type EventType string
+
+const (
+	EventChallengeRotated EventType = "challenge_rotated"
+	EventDifficultyChanged EventType = "difficulty_changed"
+	EventAirdropSent      EventType = "airdrop_sent"
+	EventRPCSwitched      EventType = "rpc_switched"
+)
+
+// Event is the JSON payload emitted to `/events` subscribers.
+type Event struct {
+	Type      EventType      `json:"type"`
+	Timestamp int64          `json:"timestamp"`
+	Data      map[string]any `json:"data"`
+}
+
+// EventBus fans out operational events to any number of subscribers (e.g. the
+// `/events` WebSocket handler). An event is dropped for a subscriber whose
+// buffer is full rather than allowed to block publishers; the subscriber
+// itself stays registered.
+type EventBus struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+// NewEventBus constructs an empty EventBus.
+func NewEventBus() *EventBus {
+	return &EventBus{subs: map[chan Event]struct{}{}}
+}
+
+// Subscribe registers a new listener and returns its channel along with an
+// unsubscribe function that must be called when the caller is done.
+func (b *EventBus) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, 16)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[ch]; ok {
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}
+	return ch, unsubscribe
+}
+
+func (b *EventBus) publish(t EventType, data map[string]any) {
+	evt := Event{Type: t, Timestamp: time.Now().Unix(), Data: data}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- evt:
+		default:
+			// Drop the event for this subscriber rather than block the faucet.
+		}
+	}
+}
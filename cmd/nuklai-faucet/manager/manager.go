@@ -27,6 +27,17 @@ import (
 	"go.uber.org/zap"
 )
 
+// ErrNetworkFeeTooHigh and ErrInsufficientBalance are the two sendFunds
+// failure modes finalizeSolve recognizes as an actual balance shortfall,
+// recorded under DenyInsufficientFunds; every other sendFunds error (RPC
+// failures, nonce races, on-chain rejection for other reasons) is recorded
+// under DenySubmissionFailed instead so the "denials by reason" metric
+// isn't corrupted by reasons that aren't about funds.
+var (
+	ErrNetworkFeeTooHigh   = errors.New("network fee too high")
+	ErrInsufficientBalance = errors.New("insufficient balance")
+)
+
 type Manager struct {
 	log    logging.Logger
 	config *config.Config
@@ -36,14 +47,96 @@ type Manager struct {
 
 	factory *auth.ED25519Factory
 
-	l            sync.RWMutex
-	t            *timer.Timer
-	lastRotation int64
-	salt         []byte
-	difficulty   uint16
-	solutions    set.Set[ids.ID]
-	cancelFunc   context.CancelFunc
-	wg           sync.WaitGroup // to control the Run method execution
+	cancelFunc context.CancelFunc
+	wg         sync.WaitGroup // to control the Run method execution
+
+	assetsL sync.RWMutex
+	assets  map[ids.ID]*assetFaucet
+	// running is true for the duration of Run, so RegisterAsset knows
+	// whether it must dispatch a newly-registered asset's timer itself or
+	// leave that to Run's own dispatch loop.
+	running bool
+
+	limiter *Limiter
+	metrics *Metrics
+	events  *EventBus
+	store   StateStore
+	batcher *batcher
+}
+
+// AssetConfig configures fauceting for a single asset. The native asset
+// (ids.Empty) is always configured from the top-level config fields; any
+// other asset must be registered explicitly via RegisterAsset.
+type AssetConfig struct {
+	Amount                uint64 // amount to send per solved challenge
+	TargetDurationPerSalt int64  // seconds
+	StartDifficulty       uint16
+	SolutionsPerSalt      int
+	// Scheme selects the PoW algorithm solvers must use for this asset. The
+	// zero value is challenge.HashPrefix, matching the faucet's original
+	// behavior.
+	Scheme challenge.SchemeID
+}
+
+// assetFaucet tracks the scheme/config/timer used to faucet a single asset.
+// The durable salt/difficulty/last-rotation state and the set of accepted
+// solutions live in the Manager's StateStore, not here, so that multiple
+// faucet instances rotate and validate against the same challenge instead of
+// each keeping its own.
+type assetFaucet struct {
+	asset  ids.ID
+	config AssetConfig
+	scheme challenge.Scheme
+
+	l sync.Mutex
+	t *timer.Timer
+	// solutions is a local, per-instance tally of solves seen since the last
+	// rotation this instance knows about, used only to decide when this
+	// instance should itself trigger a rotation. It undercounts in a
+	// multi-instance deployment, since it can't see solves another instance
+	// accepted; StateStore.ProposeSolve, not this set, is what prevents the
+	// same solution being paid out twice.
+	solutions set.Set[ids.ID]
+}
+
+// seedChallenge ensures store has a challenge recorded for af.asset,
+// generating and recording one via af.scheme if it doesn't already (a fresh
+// store, or the first instance to register this asset). It leaves an
+// existing challenge untouched, so an instance joining a cluster where other
+// instances are already running picks up their current salt instead of
+// clobbering it.
+func seedChallenge(store StateStore, af *assetFaucet) error {
+	salt, _, _, err := store.GetChallenge(af.asset)
+	if err != nil {
+		return err
+	}
+	if len(salt) > 0 {
+		return nil
+	}
+	salt, err = af.scheme.New()
+	if err != nil {
+		return err
+	}
+	return store.RotateChallenge(af.asset, salt, af.config.StartDifficulty, time.Now().Unix())
+}
+
+// newAssetFaucet builds the in-process tracking for [asset] and seeds its
+// challenge into m.store if one isn't already recorded there.
+func (m *Manager) newAssetFaucet(asset ids.ID, cfg AssetConfig) (*assetFaucet, error) {
+	scheme, err := challenge.Get(cfg.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	af := &assetFaucet{
+		asset:     asset,
+		config:    cfg,
+		scheme:    scheme,
+		solutions: set.NewSet[ids.ID](cfg.SolutionsPerSalt),
+	}
+	if err := seedChallenge(m.store, af); err != nil {
+		return nil, err
+	}
+	return af, nil
 }
 
 func New(logger logging.Logger, config *config.Config) (*Manager, error) {
@@ -69,145 +162,400 @@ func New(logger logging.Logger, config *config.Config) (*Manager, error) {
 		return nil, err
 	}
 	ncli := nrpc.NewJSONRPCClient(config.NuklaiRPC, networkID, chainID)
-	m := &Manager{log: logger, config: config, cli: cli, ncli: ncli, factory: auth.NewED25519Factory(config.PrivateKey()), cancelFunc: cancel}
-	m.lastRotation = time.Now().Unix()
-	m.difficulty = m.config.StartDifficulty
-	m.solutions = set.NewSet[ids.ID](m.config.SolutionsPerSalt)
-	m.salt, err = challenge.New()
+	m := &Manager{
+		log:        logger,
+		config:     config,
+		cli:        cli,
+		ncli:       ncli,
+		factory:    auth.NewED25519Factory(config.PrivateKey()),
+		cancelFunc: cancel,
+		assets:     map[ids.ID]*assetFaucet{},
+		metrics:    NewMetrics(),
+		events:     NewEventBus(),
+		store:      NewInMemoryStateStore(),
+	}
+	m.batcher = newBatcher(m, BatchConfig{})
+
+	nativeCfg := AssetConfig{
+		Amount:                m.config.Amount,
+		TargetDurationPerSalt: m.config.TargetDurationPerSalt,
+		StartDifficulty:       m.config.StartDifficulty,
+		SolutionsPerSalt:      m.config.SolutionsPerSalt,
+	}
+	native, err := m.newAssetFaucet(ids.Empty, nativeCfg)
 	if err != nil {
 		return nil, err
 	}
+	m.assets[ids.Empty] = native
+
 	bal, err := ncli.Balance(ctx, m.config.AddressBech32(), ids.Empty)
 	if err != nil {
 		return nil, err
 	}
+	_, difficulty, _, err := m.store.GetChallenge(ids.Empty)
+	if err != nil {
+		return nil, err
+	}
 	m.log.Info("faucet initialized",
 		zap.String("address", m.config.AddressBech32()),
-		zap.Uint16("difficulty", m.difficulty),
+		zap.Uint16("difficulty", difficulty),
 		zap.String("balance", utils.FormatBalance(bal, consts.Decimals)),
 	)
-	m.t = timer.NewTimer(m.updateDifficulty)
+	native.t = timer.NewTimer(func() { m.updateDifficulty(native) })
+	m.metrics.setDifficulty(ids.Empty, difficulty)
+	m.metrics.setBalance(bal)
 	return m, nil
 }
 
+// Metrics returns the Prometheus registry to serve on a `/metrics` endpoint.
+func (m *Manager) Metrics() *Metrics {
+	return m.metrics
+}
+
+// Events returns the event bus backing the `/events` WebSocket stream.
+func (m *Manager) Events() *EventBus {
+	return m.events
+}
+
+// RegisterAsset configures fauceting for an additional asset (e.g. one minted
+// via actions.CreateAsset/MintAsset). It returns an error if the asset is
+// already registered.
+func (m *Manager) RegisterAsset(asset ids.ID, cfg AssetConfig) error {
+	m.assetsL.Lock()
+	defer m.assetsL.Unlock()
+
+	if _, ok := m.assets[asset]; ok {
+		return fmt.Errorf("asset %s is already registered", asset)
+	}
+	af, err := m.newAssetFaucet(asset, cfg)
+	if err != nil {
+		return err
+	}
+	af.t = timer.NewTimer(func() { m.updateDifficulty(af) })
+	m.assets[asset] = af
+	if m.running {
+		// Run's dispatch loop already started before this asset was
+		// registered, so it won't pick this one up; dispatch it ourselves.
+		af.t.SetTimeoutIn(time.Duration(af.config.TargetDurationPerSalt) * time.Second)
+		go af.t.Dispatch()
+	}
+	return nil
+}
+
+// SetLimiter installs the rate limiter used to gate airdrops. It must be
+// called before Run starts serving challenges.
+func (m *Manager) SetLimiter(l *Limiter) {
+	m.limiter = l
+}
+
+// SetStateStore swaps in a shared StateStore (e.g. a RaftStateStore),
+// letting multiple faucet instances coordinate salt/difficulty/solved-state
+// instead of each tracking it only in local memory. It seeds a challenge in
+// store for every asset already registered, if store doesn't already have
+// one (e.g. from another instance that registered it first). Must be called
+// before Run starts serving challenges.
+func (m *Manager) SetStateStore(store StateStore) error {
+	m.assetsL.RLock()
+	defer m.assetsL.RUnlock()
+
+	for _, af := range m.assets {
+		if err := seedChallenge(store, af); err != nil {
+			return err
+		}
+	}
+	m.store = store
+	return nil
+}
+
+// SetBatchConfig overrides how solved challenges are grouped into batch
+// transactions. Must be called before Run starts serving challenges.
+func (m *Manager) SetBatchConfig(cfg BatchConfig) {
+	m.batcher = newBatcher(m, cfg)
+}
+
+// ResetLimit clears the rate-limit budget for [addr], exposed so operators can
+// unblock a legitimate recipient without restarting the faucet.
+func (m *Manager) ResetLimit(addr codec.Address) error {
+	if m.limiter == nil {
+		return errors.New("rate limiting is not enabled")
+	}
+	return m.limiter.Reset(addr)
+}
+
+func (m *Manager) getAssetFaucet(asset ids.ID) (*assetFaucet, error) {
+	m.assetsL.RLock()
+	defer m.assetsL.RUnlock()
+
+	af, ok := m.assets[asset]
+	if !ok {
+		return nil, fmt.Errorf("asset %s is not configured for fauceting", asset)
+	}
+	return af, nil
+}
+
 func (m *Manager) Run(ctx context.Context) error {
-	m.t.SetTimeoutIn(time.Duration(m.config.TargetDurationPerSalt) * time.Second)
-	go m.t.Dispatch()
+	m.batcher.start(ctx)
+	defer m.batcher.stop()
+
+	m.assetsL.Lock()
+	m.running = true
+	for _, af := range m.assets {
+		af.t.SetTimeoutIn(time.Duration(af.config.TargetDurationPerSalt) * time.Second)
+		go af.t.Dispatch()
+	}
+	m.assetsL.Unlock()
+
 	<-ctx.Done()
-	m.t.Stop()
+
+	m.assetsL.Lock()
+	m.running = false
+	for _, af := range m.assets {
+		af.t.Stop()
+	}
+	m.assetsL.Unlock()
 	return ctx.Err()
 }
 
-func (m *Manager) updateDifficulty() {
-	m.l.Lock()
-	defer m.l.Unlock()
+func (m *Manager) updateDifficulty(af *assetFaucet) {
+	af.l.Lock()
+	defer af.l.Unlock()
+
+	_, difficulty, lastRotation, err := m.store.GetChallenge(af.asset)
+	if err != nil {
+		m.log.Error("failed to read faucet challenge", zap.Stringer("asset", af.asset), zap.Error(err))
+		return
+	}
 
 	// If time since [lastRotation] is within half of the target duration,
 	// we attempted to update difficulty when we just reset during block processing.
 	now := time.Now().Unix()
-	if now-m.lastRotation < m.config.TargetDurationPerSalt/2 {
+	if now-lastRotation < af.config.TargetDurationPerSalt/2 {
 		return
 	}
 
 	// Decrease difficulty if there are no solutions in this period
-	if m.difficulty > m.config.StartDifficulty && m.solutions.Len() == 0 {
-		m.difficulty--
-		m.log.Info("decreasing faucet difficulty", zap.Uint16("new difficulty", m.difficulty))
+	if difficulty > af.config.StartDifficulty && af.solutions.Len() == 0 {
+		difficulty--
+		m.log.Info("decreasing faucet difficulty", zap.Stringer("asset", af.asset), zap.Uint16("new difficulty", difficulty))
+		m.events.publish(EventDifficultyChanged, map[string]any{"asset": af.asset.String(), "difficulty": difficulty})
 	}
-	m.lastRotation = time.Now().Unix()
-	salt, err := challenge.New()
+	salt, err := af.scheme.New()
 	if err != nil {
 		panic(err)
 	}
-	m.salt = salt
-	m.solutions.Clear()
-	m.t.SetTimeoutIn(time.Duration(m.config.TargetDurationPerSalt) * time.Second)
+	if err := m.store.RotateChallenge(af.asset, salt, difficulty, now); err != nil {
+		m.log.Error("failed to persist rotated faucet challenge", zap.Stringer("asset", af.asset), zap.Error(err))
+		return
+	}
+	if err := m.store.ClearSolved(af.asset); err != nil {
+		m.log.Error("failed to clear solved solutions after rotation", zap.Stringer("asset", af.asset), zap.Error(err))
+	}
+	af.solutions.Clear()
+	af.t.SetTimeoutIn(time.Duration(af.config.TargetDurationPerSalt) * time.Second)
+
+	m.metrics.setDifficulty(af.asset, difficulty)
+	m.events.publish(EventChallengeRotated, map[string]any{"asset": af.asset.String()})
 }
 
 func (m *Manager) GetFaucetAddress(_ context.Context) (codec.Address, error) {
 	return m.config.Address(), nil
 }
 
-func (m *Manager) GetChallenge(_ context.Context) ([]byte, uint16, error) {
-	m.l.RLock()
-	defer m.l.RUnlock()
+// GetChallenge returns the current salt/difficulty/scheme for the native asset.
+func (m *Manager) GetChallenge(ctx context.Context) ([]byte, uint16, challenge.SchemeID, error) {
+	return m.GetChallengeForAsset(ctx, ids.Empty)
+}
 
-	return m.salt, m.difficulty, nil
+// GetChallengeForAsset returns the current salt/difficulty/scheme used to
+// faucet [asset]. The scheme identifier tells the solver (and, on submission,
+// SolveChallengeForAsset) which challenge.Scheme to use.
+func (m *Manager) GetChallengeForAsset(_ context.Context, asset ids.ID) ([]byte, uint16, challenge.SchemeID, error) {
+	af, err := m.getAssetFaucet(asset)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	salt, difficulty, _, err := m.store.GetChallenge(asset)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return salt, difficulty, af.scheme.ID(), nil
 }
 
-func (m *Manager) sendFunds(ctx context.Context, destination codec.Address, amount uint64) (ids.ID, uint64, error) {
+func (m *Manager) sendFunds(ctx context.Context, asset ids.ID, destination codec.Address, amount uint64) (ids.ID, uint64, error) {
 	parser, err := m.ncli.Parser(ctx)
 	if err != nil {
 		return ids.Empty, 0, err
 	}
 	submit, tx, maxFee, err := m.cli.GenerateTransaction(ctx, parser, nil, &actions.Transfer{
 		To:    destination,
-		Asset: ids.Empty,
+		Asset: asset,
 		Value: amount,
 	}, m.factory)
 	if err != nil {
 		return ids.Empty, 0, err
 	}
-	if amount < maxFee {
+	if asset == ids.Empty && amount < maxFee {
 		m.log.Warn("abandoning airdrop because network fee is greater than amount", zap.String("maxFee", utils.FormatBalance(maxFee, consts.Decimals)))
-		return ids.Empty, 0, errors.New("network fee too high")
+		return ids.Empty, 0, ErrNetworkFeeTooHigh
 	}
-	bal, err := m.ncli.Balance(ctx, m.config.AddressBech32(), ids.Empty)
+	bal, err := m.ncli.Balance(ctx, m.config.AddressBech32(), asset)
 	if err != nil {
 		return ids.Empty, 0, err
 	}
-	if bal < maxFee+amount {
+	if asset == ids.Empty {
+		m.metrics.setBalance(bal)
+	}
+	required := amount
+	if asset == ids.Empty {
+		required += maxFee
+	}
+	if bal < required {
 		// This is a "best guess" heuristic for balance as there may be txs in-flight.
-		m.log.Warn("faucet has insufficient funds", zap.String("balance", utils.FormatBalance(bal, consts.Decimals)))
-		return ids.Empty, 0, errors.New("insufficient balance")
+		m.log.Warn("faucet has insufficient funds", zap.Stringer("asset", asset), zap.String("balance", utils.FormatBalance(bal, consts.Decimals)))
+		return ids.Empty, 0, ErrInsufficientBalance
 	}
 	return tx.ID(), maxFee, submit(ctx)
 }
 
-func (m *Manager) SolveChallenge(ctx context.Context, solver codec.Address, salt []byte, solution []byte) (ids.ID, uint64, error) {
-	m.l.Lock()
-	defer m.l.Unlock()
+// SolveChallenge verifies a solution to the native asset's challenge and, on
+// success, returns a handle that resolves once the airdrop has been
+// submitted.
+func (m *Manager) SolveChallenge(ctx context.Context, solver codec.Address, salt []byte, solution []byte) (*SolveHandle, error) {
+	return m.SolveChallengeForAsset(ctx, ids.Empty, solver, salt, solution)
+}
 
-	// Ensure solution is valid
-	if !bytes.Equal(m.salt, salt) {
-		return ids.Empty, 0, errors.New("salt expired")
+// SolveChallengeForAsset verifies a solution to [asset]'s challenge and, on
+// success, queues the asset's configured amount to be airdropped to solver.
+// The transfer may be packed into the same transaction as other solves
+// accepted around the same time (see BatchConfig); the returned SolveHandle
+// resolves to the batch's transaction ID once it has been submitted.
+func (m *Manager) SolveChallengeForAsset(ctx context.Context, asset ids.ID, solver codec.Address, salt []byte, solution []byte) (*SolveHandle, error) {
+	start := time.Now()
+	af, err := m.getAssetFaucet(asset)
+	if err != nil {
+		return nil, err
 	}
-	if !challenge.Verify(salt, solution, m.difficulty) {
-		return ids.Empty, 0, errors.New("invalid solution")
+
+	// Ensure solution is valid against the challenge shared via m.store, not
+	// just this instance's own view of it.
+	currentSalt, difficulty, _, err := m.store.GetChallenge(asset)
+	if err != nil {
+		return nil, err
 	}
-	solutionID := utils.ToID(solution)
-	if m.solutions.Contains(solutionID) {
-		return ids.Empty, 0, errors.New("duplicate solution")
+	if !bytes.Equal(currentSalt, salt) {
+		m.recordDenial(DenySaltExpired)
+		return nil, errors.New("salt expired")
 	}
+	if !af.scheme.Verify(salt, solution, difficulty) {
+		m.recordDenial(DenyInvalidSolution)
+		return nil, errors.New("invalid solution")
+	}
+	solutionID := utils.ToID(solution)
 
-	// Issue transaction
-	txID, maxFee, err := m.sendFunds(ctx, solver, m.config.Amount)
+	// Durably propose the solve before submitting any transaction: on a
+	// Raft-backed store this only returns applied=true once a quorum has
+	// committed, so a leader failover mid-airdrop can't cause a replacement
+	// leader to re-issue the same solution.
+	if !m.store.IsLeader() {
+		return nil, ErrNotLeader
+	}
+	applied, err := m.store.ProposeSolve(asset, solutionID)
 	if err != nil {
-		return ids.Empty, 0, err
+		return nil, err
+	}
+	if !applied {
+		m.recordDenial(DenyDuplicateSolution)
+		return nil, errors.New("duplicate solution")
 	}
+
+	// Enforce per-address/per-IP rate limits before issuing a transfer.
+	if m.limiter != nil {
+		if ok, reason := m.limiter.Allow(solver, ipFromContext(ctx)); !ok {
+			m.metrics.recordFailure(reason)
+			return nil, fmt.Errorf("denied: %s", reason)
+		}
+	}
+
+	// Queue the transfer rather than submitting it immediately: the batcher
+	// may pack it into the same transaction as other solves accepted around
+	// the same time to amortize network fees.
+	internal := make(chan transferResult, 1)
+	m.batcher.enqueue(transferRequest{asset: asset, destination: solver, amount: af.config.Amount, resultCh: internal})
+
+	external := make(chan transferResult, 1)
+	go m.finalizeSolve(af, solver, solutionID, start, internal, external)
+
+	return &SolveHandle{resultCh: external}, nil
+}
+
+// finalizeSolve waits for the batch containing solutionID's transfer to be
+// submitted, records metrics/events for the outcome, and - once the asset
+// has paid out its configured number of solutions - rolls its salt. It runs
+// asynchronously so SolveChallengeForAsset doesn't block on batch submission.
+func (m *Manager) finalizeSolve(af *assetFaucet, solver codec.Address, solutionID ids.ID, start time.Time, in <-chan transferResult, out chan<- transferResult) {
+	res := <-in
+	m.metrics.observeSolveLatency(time.Since(start).Seconds())
+	if res.err != nil {
+		if errors.Is(res.err, ErrNetworkFeeTooHigh) || errors.Is(res.err, ErrInsufficientBalance) {
+			m.recordDenial(DenyInsufficientFunds)
+		} else {
+			m.log.Warn("airdrop submission failed", zap.Stringer("asset", af.asset), zap.Error(res.err))
+			m.recordDenial(DenySubmissionFailed)
+		}
+		out <- res
+		return
+	}
+
 	m.log.Info("fauceted funds",
-		zap.Stringer("txID", txID),
-		zap.String("max fee", utils.FormatBalance(maxFee, consts.Decimals)),
+		zap.Stringer("asset", af.asset),
+		zap.Stringer("txID", res.txID),
+		zap.String("max fee", utils.FormatBalance(res.fee, consts.Decimals)),
 		zap.String("destination", codec.MustAddressBech32(consts.HRP, solver)),
-		zap.String("amount", utils.FormatBalance(m.config.Amount, consts.Decimals)),
+		zap.String("amount", utils.FormatBalance(af.config.Amount, consts.Decimals)),
 	)
-	m.solutions.Add(solutionID)
-
-	// Roll salt if hit expected solutions
-	if m.solutions.Len() >= m.config.SolutionsPerSalt {
-		m.difficulty++
-		m.log.Info("increasing faucet difficulty", zap.Uint16("new difficulty", m.difficulty))
-		m.lastRotation = time.Now().Unix()
-		m.salt, err = challenge.New()
+	m.metrics.recordAirdrop(af.asset)
+	m.events.publish(EventAirdropSent, map[string]any{
+		"asset":       af.asset.String(),
+		"destination": codec.MustAddressBech32(consts.HRP, solver),
+		"txID":        res.txID.String(),
+		"amount":      af.config.Amount,
+	})
+
+	af.l.Lock()
+	af.solutions.Add(solutionID)
+	shouldRotate := af.solutions.Len() >= af.config.SolutionsPerSalt
+	if shouldRotate {
+		af.solutions.Clear()
+	}
+	af.l.Unlock()
+
+	if shouldRotate {
+		_, difficulty, _, err := m.store.GetChallenge(af.asset)
 		if err != nil {
-			// Should never happen
-			return ids.Empty, 0, err
+			m.log.Error("failed to read faucet challenge before rotating", zap.Stringer("asset", af.asset), zap.Error(err))
+			out <- res
+			return
+		}
+		difficulty++
+		m.log.Info("increasing faucet difficulty", zap.Stringer("asset", af.asset), zap.Uint16("new difficulty", difficulty))
+		m.metrics.setDifficulty(af.asset, difficulty)
+		m.events.publish(EventDifficultyChanged, map[string]any{"asset": af.asset.String(), "difficulty": difficulty})
+		if salt, err := af.scheme.New(); err == nil {
+			if err := m.store.RotateChallenge(af.asset, salt, difficulty, time.Now().Unix()); err != nil {
+				m.log.Error("failed to persist rotated faucet challenge", zap.Stringer("asset", af.asset), zap.Error(err))
+			}
+		} else {
+			m.log.Error("failed to roll faucet salt", zap.Stringer("asset", af.asset), zap.Error(err))
+		}
+		if err := m.store.ClearSolved(af.asset); err != nil {
+			m.log.Error("failed to clear solved solutions after rotation", zap.Stringer("asset", af.asset), zap.Error(err))
 		}
-		m.solutions.Clear()
-		m.t.Cancel()
-		m.t.SetTimeoutIn(time.Duration(m.config.TargetDurationPerSalt) * time.Second)
+		af.t.Cancel()
+		af.t.SetTimeoutIn(time.Duration(af.config.TargetDurationPerSalt) * time.Second)
 	}
-	return txID, m.config.Amount, nil
+
+	out <- res
 }
 
 func (m *Manager) RestartRun(ctx context.Context) {
@@ -229,8 +577,8 @@ func (m *Manager) RestartRun(ctx context.Context) {
 }
 
 func (m *Manager) UpdateNuklaiRPC(ctx context.Context, newNuklaiRPCUrl string) error {
-	m.l.Lock()
-	defer m.l.Unlock()
+	m.assetsL.Lock()
+	defer m.assetsL.Unlock()
 
 	m.log.Info("Updating nuklaiRPC URL", zap.String("old URL", m.config.NuklaiRPC), zap.String("new URL", newNuklaiRPCUrl))
 
@@ -250,30 +598,37 @@ func (m *Manager) UpdateNuklaiRPC(ctx context.Context, newNuklaiRPCUrl string) e
 	m.cli = cli
 	m.ncli = nrpc.NewJSONRPCClient(newNuklaiRPCUrl, networkID, chainID)
 
-	// Reinitialize dependent properties
-	m.salt, err = challenge.New()
-	if err != nil {
-		m.log.Error("Failed to generate new salt", zap.Error(err))
-		return fmt.Errorf("failed to generate new salt: %w", err)
+	// Reinitialize dependent asset faucets, preserving their configs
+	for asset, af := range m.assets {
+		cfg := af.config
+		newAf, err := m.newAssetFaucet(asset, cfg)
+		if err != nil {
+			m.log.Error("Failed to reinitialize asset faucet", zap.Stringer("asset", asset), zap.Error(err))
+			return fmt.Errorf("failed to reinitialize asset faucet %s: %w", asset, err)
+		}
+		newAf.t = timer.NewTimer(func() { m.updateDifficulty(newAf) })
+		m.assets[asset] = newAf
 	}
-	m.solutions = set.NewSet[ids.ID](m.config.SolutionsPerSalt)
-	m.difficulty = m.config.StartDifficulty
-	m.lastRotation = time.Now().Unix()
 
 	bal, err := m.ncli.Balance(ctx, m.config.AddressBech32(), ids.Empty)
 	if err != nil {
 		return err
 	}
-	m.t = timer.NewTimer(m.updateDifficulty)
 
+	_, difficulty, _, err := m.store.GetChallenge(ids.Empty)
+	if err != nil {
+		return err
+	}
 	m.log.Info("RPC client has been updated and manager reinitialized",
 		zap.String("new RPC URL", newNuklaiRPCUrl),
 		zap.Uint32("network ID", networkID),
 		zap.String("chain ID", chainID.String()),
 		zap.String("address", m.config.AddressBech32()),
-		zap.Uint16("difficulty", m.difficulty),
+		zap.Uint16("difficulty", difficulty),
 		zap.String("balance", utils.FormatBalance(bal, consts.Decimals)),
 	)
+	m.metrics.setBalance(bal)
+	m.events.publish(EventRPCSwitched, map[string]any{"url": newNuklaiRPCUrl, "networkID": networkID, "chainID": chainID.String()})
 
 	// Restart the Run function safely
 	m.RestartRun(ctx)
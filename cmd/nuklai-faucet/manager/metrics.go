@@ -0,0 +1,89 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package manager
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics exposes Prometheus instrumentation for faucet operations. It is
+// registered alongside the faucet's JSON-RPC server so operators get the
+// same `/metrics` visibility larger chain node deployments provide.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	solveLatency    prometheus.Histogram
+	difficulty      *prometheus.GaugeVec
+	faucetBalance   prometheus.Gauge
+	airdropsIssued  *prometheus.CounterVec
+	airdropsFailed  *prometheus.CounterVec
+}
+
+// NewMetrics constructs a Metrics instance registered against a fresh
+// Prometheus registry, suitable for serving on a `/metrics` endpoint.
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		solveLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "nuklai_faucet",
+			Name:      "challenge_solve_latency_seconds",
+			Help:      "Time to verify a challenge solution and submit the resulting airdrop transaction.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		difficulty: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "nuklai_faucet",
+			Name:      "difficulty",
+			Help:      "Current PoW difficulty, labeled by asset.",
+		}, []string{"asset"}),
+		faucetBalance: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "nuklai_faucet",
+			Name:      "balance",
+			Help:      "Current native asset balance held by the faucet address.",
+		}),
+		airdropsIssued: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "nuklai_faucet",
+			Name:      "airdrops_issued_total",
+			Help:      "Number of airdrops successfully submitted, labeled by asset.",
+		}, []string{"asset"}),
+		airdropsFailed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "nuklai_faucet",
+			Name:      "airdrops_failed_total",
+			Help:      "Number of airdrops denied, labeled by reason.",
+		}, []string{"reason"}),
+	}
+	m.registry.MustRegister(
+		m.solveLatency,
+		m.difficulty,
+		m.faucetBalance,
+		m.airdropsIssued,
+		m.airdropsFailed,
+	)
+	return m
+}
+
+// Registry returns the Prometheus registry to serve on `/metrics`.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+func (m *Metrics) observeSolveLatency(seconds float64) {
+	m.solveLatency.Observe(seconds)
+}
+
+func (m *Metrics) setDifficulty(asset ids.ID, difficulty uint16) {
+	m.difficulty.WithLabelValues(asset.String()).Set(float64(difficulty))
+}
+
+func (m *Metrics) setBalance(balance uint64) {
+	m.faucetBalance.Set(float64(balance))
+}
+
+func (m *Metrics) recordAirdrop(asset ids.ID) {
+	m.airdropsIssued.WithLabelValues(asset.String()).Inc()
+}
+
+func (m *Metrics) recordFailure(reason DenialReason) {
+	m.airdropsFailed.WithLabelValues(string(reason)).Inc()
+}
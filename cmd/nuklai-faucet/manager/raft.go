@@ -0,0 +1,295 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
+)
+
+// RaftConfig configures a Raft-replicated StateStore.
+type RaftConfig struct {
+	NodeID   string
+	BindAddr string
+	DataDir  string
+	Bootstrap bool // true for the node that forms a brand-new single-node cluster
+}
+
+// command is a single raft log entry. Every faucet state mutation (challenge
+// rotation, solve proposals, nonce allocation) goes through the log so that
+// followers converge on the same outcome and SolveChallenge only submits a
+// transaction after the corresponding command has committed on a quorum.
+type command struct {
+	Op           string `json:"op"` // "rotate", "solve", or "nonce"
+	Asset        ids.ID `json:"asset,omitempty"`
+	Salt         []byte `json:"salt,omitempty"`
+	Difficulty   uint16 `json:"difficulty,omitempty"`
+	Now          int64  `json:"now,omitempty"`
+	SolutionID   ids.ID `json:"solutionID,omitempty"`
+	NonceKey     string `json:"nonceKey,omitempty"`
+}
+
+type commandResult struct {
+	applied bool
+	nonce   uint64
+}
+
+var _ StateStore = (*RaftStateStore)(nil)
+
+// RaftStateStore replicates salt/solutions/lastRotation/difficulty/nonce
+// state across a cluster of faucet instances via hashicorp/raft. Only the
+// leader proposes log entries, and every replica applies committed ones; on
+// a follower, mutations fail with ErrNotLeader and LeaderAddr names the
+// leader to send the request to instead.
+type RaftStateStore struct {
+	raft *raft.Raft
+	fsm  *raftFSM
+}
+
+// NewRaftStateStore bootstraps (or joins) a raft-backed StateStore. Callers
+// add peers after startup via the returned store's Join method on the leader,
+// typically from a `raft join`/`raft bootstrap` faucet subcommand.
+func NewRaftStateStore(cfg RaftConfig) (*RaftStateStore, error) {
+	if err := os.MkdirAll(cfg.DataDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create raft data dir: %w", err)
+	}
+
+	fsm := &raftFSM{inner: NewInMemoryStateStore()}
+
+	raftCfg := raft.DefaultConfig()
+	raftCfg.LocalID = raft.ServerID(cfg.NodeID)
+
+	addr, err := net.ResolveTCPAddr("tcp", cfg.BindAddr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid raft bind address %s: %w", cfg.BindAddr, err)
+	}
+	transport, err := raft.NewTCPTransport(cfg.BindAddr, addr, 3, 10*time.Second, os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create raft transport: %w", err)
+	}
+
+	snapshots, err := raft.NewFileSnapshotStore(cfg.DataDir, 2, os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create raft snapshot store: %w", err)
+	}
+
+	logStore, err := raftboltdb.NewBoltStore(filepath.Join(cfg.DataDir, "raft-log.bolt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create raft log store: %w", err)
+	}
+	stableStore, err := raftboltdb.NewBoltStore(filepath.Join(cfg.DataDir, "raft-stable.bolt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create raft stable store: %w", err)
+	}
+
+	r, err := raft.NewRaft(raftCfg, fsm, logStore, stableStore, snapshots, transport)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start raft: %w", err)
+	}
+
+	if cfg.Bootstrap {
+		r.BootstrapCluster(raft.Configuration{
+			Servers: []raft.Server{{ID: raftCfg.LocalID, Address: transport.LocalAddr()}},
+		})
+	}
+
+	return &RaftStateStore{raft: r, fsm: fsm}, nil
+}
+
+// Join adds [nodeID] at [addr] as a voter, called on the current leader when
+// a new faucet instance wants to join the cluster (the `raft join`
+// subcommand).
+func (s *RaftStateStore) Join(nodeID, addr string) error {
+	if s.raft.State() != raft.Leader {
+		return ErrNotLeader
+	}
+	return s.raft.AddVoter(raft.ServerID(nodeID), raft.ServerAddress(addr), 0, 10*time.Second).Error()
+}
+
+// LeaderAddr returns the address of the current raft leader so that a
+// follower can forward a SolveChallenge request to it.
+func (s *RaftStateStore) LeaderAddr() string {
+	addr, _ := s.raft.LeaderWithID()
+	return string(addr)
+}
+
+func (s *RaftStateStore) apply(cmd command) (commandResult, error) {
+	if s.raft.State() != raft.Leader {
+		return commandResult{}, ErrNotLeader
+	}
+	b, err := json.Marshal(cmd)
+	if err != nil {
+		return commandResult{}, err
+	}
+	future := s.raft.Apply(b, 10*time.Second)
+	if err := future.Error(); err != nil {
+		return commandResult{}, err
+	}
+	res, _ := future.Response().(commandResult)
+	return res, nil
+}
+
+func (s *RaftStateStore) GetChallenge(asset ids.ID) ([]byte, uint16, int64, error) {
+	// Reads are served locally from the FSM; they don't need to go through
+	// the log since a stale challenge just means a solver's salt is rejected
+	// and retried, never a double-spend.
+	return s.fsm.inner.GetChallenge(asset)
+}
+
+func (s *RaftStateStore) RotateChallenge(asset ids.ID, salt []byte, difficulty uint16, now int64) error {
+	_, err := s.apply(command{Op: "rotate", Asset: asset, Salt: salt, Difficulty: difficulty, Now: now})
+	return err
+}
+
+// ProposeSolve is the idempotent, quorum-gated step described by the HA
+// design: the solution hash only becomes "applied" once a majority of the
+// cluster has committed the log entry, so a leader that fails mid-airdrop
+// can't cause the replacement leader to re-issue the same solve.
+func (s *RaftStateStore) ProposeSolve(asset ids.ID, solutionID ids.ID) (bool, error) {
+	res, err := s.apply(command{Op: "solve", Asset: asset, SolutionID: solutionID})
+	if err != nil {
+		return false, err
+	}
+	return res.applied, nil
+}
+
+func (s *RaftStateStore) ClearSolved(asset ids.ID) error {
+	return s.fsm.inner.ClearSolved(asset)
+}
+
+func (s *RaftStateStore) NextNonce(key string) (uint64, error) {
+	res, err := s.apply(command{Op: "nonce", NonceKey: key})
+	if err != nil {
+		return 0, err
+	}
+	return res.nonce, nil
+}
+
+func (s *RaftStateStore) IsLeader() bool {
+	return s.raft.State() == raft.Leader
+}
+
+func (s *RaftStateStore) Close() error {
+	return s.raft.Shutdown().Error()
+}
+
+// raftFSM applies committed commands to an in-memory StateStore. Raft
+// guarantees every voter applies the same sequence of commands in the same
+// order, so each replica's InMemoryStateStore converges independently.
+type raftFSM struct {
+	inner *InMemoryStateStore
+}
+
+func (f *raftFSM) Apply(log *raft.Log) interface{} {
+	var cmd command
+	if err := json.Unmarshal(log.Data, &cmd); err != nil {
+		return commandResult{}
+	}
+	switch cmd.Op {
+	case "rotate":
+		_ = f.inner.RotateChallenge(cmd.Asset, cmd.Salt, cmd.Difficulty, cmd.Now)
+		_ = f.inner.ClearSolved(cmd.Asset)
+		return commandResult{applied: true}
+	case "solve":
+		applied, _ := f.inner.ProposeSolve(cmd.Asset, cmd.SolutionID)
+		return commandResult{applied: applied}
+	case "nonce":
+		n, _ := f.inner.NextNonce(cmd.NonceKey)
+		return commandResult{applied: true, nonce: n}
+	default:
+		return commandResult{}
+	}
+}
+
+func (f *raftFSM) Snapshot() (raft.FSMSnapshot, error) {
+	f.inner.mu.Lock()
+	defer f.inner.mu.Unlock()
+
+	snapshot := make(map[ids.ID]*inMemoryAssetState, len(f.inner.assets))
+	for asset, st := range f.inner.assets {
+		solved := make(map[ids.ID]struct{}, len(st.solved))
+		for id := range st.solved {
+			solved[id] = struct{}{}
+		}
+		snapshot[asset] = &inMemoryAssetState{
+			salt: st.salt, difficulty: st.difficulty, lastRotation: st.lastRotation, solved: solved,
+		}
+	}
+	nonces := make(map[string]uint64, len(f.inner.nonces))
+	for k, v := range f.inner.nonces {
+		nonces[k] = v
+	}
+	return &raftSnapshot{assets: snapshot, nonces: nonces}, nil
+}
+
+func (f *raftFSM) Restore(rc io.ReadCloser) error {
+	defer rc.Close()
+
+	var snap raftSnapshotData
+	if err := json.NewDecoder(rc).Decode(&snap); err != nil {
+		return err
+	}
+
+	f.inner.mu.Lock()
+	defer f.inner.mu.Unlock()
+	f.inner.assets = map[ids.ID]*inMemoryAssetState{}
+	for _, a := range snap.Assets {
+		solved := map[ids.ID]struct{}{}
+		for _, id := range a.Solved {
+			solved[id] = struct{}{}
+		}
+		f.inner.assets[a.Asset] = &inMemoryAssetState{
+			salt: a.Salt, difficulty: a.Difficulty, lastRotation: a.LastRotation, solved: solved,
+		}
+	}
+	f.inner.nonces = snap.Nonces
+	return nil
+}
+
+type raftSnapshot struct {
+	assets map[ids.ID]*inMemoryAssetState
+	nonces map[string]uint64
+}
+
+type raftSnapshotData struct {
+	Assets []assetSnapshot  `json:"assets"`
+	Nonces map[string]uint64 `json:"nonces"`
+}
+
+type assetSnapshot struct {
+	Asset        ids.ID   `json:"asset"`
+	Salt         []byte   `json:"salt"`
+	Difficulty   uint16   `json:"difficulty"`
+	LastRotation int64    `json:"lastRotation"`
+	Solved       []ids.ID `json:"solved"`
+}
+
+func (s *raftSnapshot) Persist(sink raft.SnapshotSink) error {
+	data := raftSnapshotData{Nonces: s.nonces}
+	for asset, st := range s.assets {
+		solved := make([]ids.ID, 0, len(st.solved))
+		for id := range st.solved {
+			solved = append(solved, id)
+		}
+		data.Assets = append(data.Assets, assetSnapshot{
+			Asset: asset, Salt: st.salt, Difficulty: st.difficulty, LastRotation: st.lastRotation, Solved: solved,
+		})
+	}
+	if err := json.NewEncoder(sink).Encode(data); err != nil {
+		sink.Cancel()
+		return err
+	}
+	return sink.Close()
+}
+
+func (*raftSnapshot) Release() {}
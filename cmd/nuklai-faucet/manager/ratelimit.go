@@ -0,0 +1,314 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package manager
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/nuklai/nuklaivm/consts"
+	"go.etcd.io/bbolt"
+	"go.uber.org/zap"
+)
+
+// requestIPKey is the context key callers (e.g. the JSON-RPC handler) use to
+// thread the solver's source IP through to the rate limiter.
+type requestIPKey struct{}
+
+// WithRequestIP returns a context carrying [ip] for use by the rate limiter.
+func WithRequestIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, requestIPKey{}, ip)
+}
+
+func ipFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(requestIPKey{}).(string)
+	return ip
+}
+
+// recordDenial tallies a denial reason against both the limiter (if
+// configured) and the Prometheus failure counter.
+func (m *Manager) recordDenial(reason DenialReason) {
+	if m.limiter != nil {
+		m.limiter.recordDenial(reason)
+	}
+	m.metrics.recordFailure(reason)
+}
+
+// DenialReason enumerates why SolveChallenge refused to issue an airdrop.
+type DenialReason string
+
+const (
+	DenySaltExpired       DenialReason = "salt_expired"
+	DenyInvalidSolution   DenialReason = "invalid_solution"
+	DenyDuplicateSolution DenialReason = "duplicate_solution"
+	DenyRateLimited       DenialReason = "rate_limited"
+	DenyInsufficientFunds DenialReason = "insufficient_funds"
+	DenyBlocked           DenialReason = "blocked"
+	// DenySubmissionFailed covers every sendFunds failure that isn't a known
+	// balance shortfall (RPC errors, nonce races, on-chain rejection for
+	// other reasons), so those don't get misreported as DenyInsufficientFunds.
+	DenySubmissionFailed DenialReason = "submission_failed"
+)
+
+var rateLimitBucket = []byte("rate_limits")
+
+// RateLimitConfig configures the per-address and per-IP token-bucket limiter
+// that guards airdrops.
+type RateLimitConfig struct {
+	RefillPerSecond float64       // tokens added per second
+	Burst           int           // max tokens a bucket can hold
+	Cooldown        time.Duration // minimum time between airdrops to the same recipient
+	DBPath          string        // bbolt file used to persist bucket state across restarts
+	ListFilePath    string        // allowlist/blocklist file, reloaded on SIGHUP
+}
+
+type tokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+	lastDrip   time.Time
+}
+
+// Limiter enforces per-address and per-IP token-bucket budgets, persists
+// bucket state to disk so restarts don't reset budgets, and hot-reloads an
+// allowlist/blocklist file on SIGHUP.
+type Limiter struct {
+	log    logging.Logger
+	config RateLimitConfig
+	db     *bbolt.DB
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	denials map[DenialReason]uint64
+	allowed map[string]bool
+	blocked map[string]bool
+	sigStop chan struct{}
+}
+
+// NewLimiter constructs a Limiter, restoring bucket state from [config.DBPath]
+// if it exists, and loading the allow/blocklist from [config.ListFilePath].
+func NewLimiter(log logging.Logger, config RateLimitConfig) (*Limiter, error) {
+	db, err := bbolt.Open(config.DBPath, 0o600, &bbolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rate limit db %s: %w", config.DBPath, err)
+	}
+	if err := db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(rateLimitBucket)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	l := &Limiter{
+		log:     log,
+		config:  config,
+		db:      db,
+		buckets: map[string]*tokenBucket{},
+		denials: map[DenialReason]uint64{},
+		allowed: map[string]bool{},
+		blocked: map[string]bool{},
+		sigStop: make(chan struct{}),
+	}
+	if err := l.restore(); err != nil {
+		return nil, err
+	}
+	if err := l.reloadList(); err != nil {
+		l.log.Warn("failed to load allow/blocklist, continuing with an empty one", zap.Error(err))
+	}
+	l.watchSIGHUP()
+	return l, nil
+}
+
+// restore loads previously-persisted bucket token counts from bbolt so that
+// a faucet restart doesn't hand out a fresh budget to every key.
+func (l *Limiter) restore() error {
+	return l.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(rateLimitBucket)
+		return b.ForEach(func(k, v []byte) error {
+			if len(v) != 16 {
+				return nil
+			}
+			tokens := math.Float64frombits(binary.BigEndian.Uint64(v[:8]))
+			lastRefill := time.Unix(int64(binary.BigEndian.Uint64(v[8:])), 0)
+			l.buckets[string(k)] = &tokenBucket{tokens: tokens, lastRefill: lastRefill}
+			return nil
+		})
+	})
+}
+
+func (l *Limiter) persist(key string, b *tokenBucket) error {
+	return l.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(rateLimitBucket)
+		buf := make([]byte, 16)
+		binary.BigEndian.PutUint64(buf[:8], math.Float64bits(b.tokens))
+		binary.BigEndian.PutUint64(buf[8:], uint64(b.lastRefill.Unix()))
+		return bucket.Put([]byte(key), buf)
+	})
+}
+
+// reloadList reads the allow/blocklist file. Each line is "allow <key>" or
+// "block <key>"; blank lines and lines starting with "#" are ignored.
+func (l *Limiter) reloadList() error {
+	if l.config.ListFilePath == "" {
+		return nil
+	}
+	f, err := os.Open(l.config.ListFilePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	allowed := map[string]bool{}
+	blocked := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		switch strings.ToLower(fields[0]) {
+		case "allow":
+			allowed[fields[1]] = true
+		case "block":
+			blocked[fields[1]] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.allowed = allowed
+	l.blocked = blocked
+	l.mu.Unlock()
+	l.log.Info("reloaded faucet allow/blocklist", zap.Int("allowed", len(allowed)), zap.Int("blocked", len(blocked)))
+	return nil
+}
+
+// watchSIGHUP reloads the allow/blocklist whenever the process receives
+// SIGHUP, so operators can update it without restarting the faucet.
+func (l *Limiter) watchSIGHUP() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-sigCh:
+				if err := l.reloadList(); err != nil {
+					l.log.Warn("failed to reload allow/blocklist on SIGHUP", zap.Error(err))
+				}
+			case <-l.sigStop:
+				signal.Stop(sigCh)
+				return
+			}
+		}
+	}()
+}
+
+func (l *Limiter) Close() error {
+	close(l.sigStop)
+	return l.db.Close()
+}
+
+// Allow checks whether [addr] fauceting from [ip] may proceed, consuming a
+// token from each of their buckets if so. It returns the denial reason when
+// refused.
+func (l *Limiter) Allow(addr codec.Address, ip string) (bool, DenialReason) {
+	addrKey := "addr:" + codec.MustAddressBech32(consts.HRP, addr)
+	ipKey := "ip:" + ip
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.blocked[addrKey] || l.blocked[ipKey] {
+		l.denials[DenyBlocked]++
+		return false, DenyBlocked
+	}
+	if l.allowed[addrKey] || l.allowed[ipKey] {
+		return true, ""
+	}
+
+	now := time.Now()
+	for _, key := range []string{addrKey, ipKey} {
+		b, ok := l.buckets[key]
+		if !ok {
+			b = &tokenBucket{tokens: float64(l.config.Burst), lastRefill: now}
+			l.buckets[key] = b
+		}
+		l.refill(b, now)
+		if b.tokens < 1 {
+			l.denials[DenyRateLimited]++
+			return false, DenyRateLimited
+		}
+		if !b.lastDrip.IsZero() && now.Sub(b.lastDrip) < l.config.Cooldown {
+			l.denials[DenyRateLimited]++
+			return false, DenyRateLimited
+		}
+	}
+
+	for _, key := range []string{addrKey, ipKey} {
+		b := l.buckets[key]
+		b.tokens--
+		b.lastDrip = now
+		if err := l.persist(key, b); err != nil {
+			l.log.Warn("failed to persist rate limit bucket", zap.String("key", key), zap.Error(err))
+		}
+	}
+	return true, ""
+}
+
+func (l *Limiter) refill(b *tokenBucket, now time.Time) {
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	b.tokens = math.Min(float64(l.config.Burst), b.tokens+elapsed*l.config.RefillPerSecond)
+	b.lastRefill = now
+}
+
+// Reset clears the rate-limit budget for [addr], allowing it to faucet
+// immediately regardless of recent history. Used by the admin ResetLimit RPC.
+func (l *Limiter) Reset(addr codec.Address) error {
+	key := "addr:" + codec.MustAddressBech32(consts.HRP, addr)
+
+	l.mu.Lock()
+	delete(l.buckets, key)
+	l.mu.Unlock()
+
+	return l.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(rateLimitBucket).Delete([]byte(key))
+	})
+}
+
+// Denials returns a snapshot of denial counts by reason.
+func (l *Limiter) Denials() map[DenialReason]uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	out := make(map[DenialReason]uint64, len(l.denials))
+	for k, v := range l.denials {
+		out[k] = v
+	}
+	return out
+}
+
+func (l *Limiter) recordDenial(reason DenialReason) {
+	l.mu.Lock()
+	l.denials[reason]++
+	l.mu.Unlock()
+}
@@ -0,0 +1,146 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package manager
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// StateStore backs the salt/solutions/lastRotation/difficulty tracked per
+// asset, plus an outbound nonce/tx tracker, so that faucet instances can
+// share state instead of each holding it only in memory. InMemoryStateStore
+// preserves today's single-instance behavior; RaftStateStore (raft.go) lets
+// multiple instances run behind a load balancer without double-spending or
+// re-issuing to the same solver.
+type StateStore interface {
+	// GetChallenge returns the current salt/difficulty for [asset].
+	GetChallenge(asset ids.ID) (salt []byte, difficulty uint16, lastRotation int64, err error)
+
+	// RotateChallenge installs a new salt/difficulty for [asset], recording
+	// [now] as the rotation time.
+	RotateChallenge(asset ids.ID, salt []byte, difficulty uint16, now int64) error
+
+	// ProposeSolve durably records that [solutionID] has been accepted for
+	// [asset] before the corresponding transfer transaction is submitted. It
+	// returns applied=false (without error) if the solution was already
+	// recorded, so callers can treat the airdrop as a duplicate rather than
+	// double-spending. On a Raft-backed store this blocks until the proposal
+	// commits on a quorum.
+	ProposeSolve(asset ids.ID, solutionID ids.ID) (applied bool, err error)
+
+	// ClearSolved forgets all recorded solutions for [asset], called when its
+	// salt rotates.
+	ClearSolved(asset ids.ID) error
+
+	// NextNonce returns the next outbound nonce for [key] (e.g. an asset ID),
+	// used to key idempotent transaction submission.
+	NextNonce(key string) (uint64, error)
+
+	// IsLeader reports whether this instance may serve SolveChallenge; when it
+	// is false, SolveChallenge returns ErrNotLeader. Always true for
+	// InMemoryStateStore.
+	IsLeader() bool
+
+	Close() error
+}
+
+var _ StateStore = (*InMemoryStateStore)(nil)
+
+// InMemoryStateStore is the default, single-instance StateStore: all state
+// lives in process memory and is lost on restart, matching the faucet's
+// original behavior.
+type InMemoryStateStore struct {
+	mu       sync.Mutex
+	assets   map[ids.ID]*inMemoryAssetState
+	nonces   map[string]uint64
+}
+
+type inMemoryAssetState struct {
+	salt         []byte
+	difficulty   uint16
+	lastRotation int64
+	solved       map[ids.ID]struct{}
+}
+
+// NewInMemoryStateStore constructs a StateStore with no shared-cluster
+// coordination, suitable for running a single faucet instance.
+func NewInMemoryStateStore() *InMemoryStateStore {
+	return &InMemoryStateStore{
+		assets: map[ids.ID]*inMemoryAssetState{},
+		nonces: map[string]uint64{},
+	}
+}
+
+func (s *InMemoryStateStore) state(asset ids.ID) *inMemoryAssetState {
+	st, ok := s.assets[asset]
+	if !ok {
+		st = &inMemoryAssetState{solved: map[ids.ID]struct{}{}}
+		s.assets[asset] = st
+	}
+	return st
+}
+
+func (s *InMemoryStateStore) GetChallenge(asset ids.ID) ([]byte, uint16, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.state(asset)
+	return st.salt, st.difficulty, st.lastRotation, nil
+}
+
+func (s *InMemoryStateStore) RotateChallenge(asset ids.ID, salt []byte, difficulty uint16, now int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.state(asset)
+	st.salt = salt
+	st.difficulty = difficulty
+	st.lastRotation = now
+	return nil
+}
+
+func (s *InMemoryStateStore) ProposeSolve(asset ids.ID, solutionID ids.ID) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.state(asset)
+	if _, ok := st.solved[solutionID]; ok {
+		return false, nil
+	}
+	st.solved[solutionID] = struct{}{}
+	return true, nil
+}
+
+func (s *InMemoryStateStore) ClearSolved(asset ids.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state(asset).solved = map[ids.ID]struct{}{}
+	return nil
+}
+
+func (s *InMemoryStateStore) NextNonce(key string) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := s.nonces[key]
+	s.nonces[key] = n + 1
+	return n, nil
+}
+
+func (*InMemoryStateStore) IsLeader() bool {
+	return true
+}
+
+func (*InMemoryStateStore) Close() error {
+	return nil
+}
+
+// ErrNotLeader is returned by a Raft-backed StateStore when a mutation is
+// attempted on an instance that is not the raft leader, and by SolveChallenge
+// on such an instance; the request is not forwarded to the leader.
+var ErrNotLeader = fmt.Errorf("this faucet instance is not the raft leader")
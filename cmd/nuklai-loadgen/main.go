@@ -0,0 +1,141 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Command nuklai-loadgen runs the loadgen package as a standalone load
+// generator against a running nuklaivm chain, issuing Transfer actions from
+// one or more pre-funded accounts until a stop condition is reached.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	hrpc "github.com/ava-labs/hypersdk/rpc"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/nuklai/nuklaivm/auth"
+	"github.com/nuklai/nuklaivm/loadgen"
+	nrpc "github.com/nuklai/nuklaivm/rpc"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	uri := flag.String("uri", "", "URI of the chain's JSON-RPC endpoint")
+	keys := flag.String("keys", "", "comma-separated hex-encoded ED25519 private keys of pre-funded accounts")
+	value := flag.Uint64("value", 1, "amount each Transfer action sends")
+	workersPerAccount := flag.Int("workers-per-account", 1, "concurrent submit workers per account")
+	inFlightPerWorker := flag.Int("in-flight-per-worker", 16, "max unconfirmed transactions per worker")
+	targetTPS := flag.Float64("target-tps", 0, "aggregate submit rate cap across all workers (0 = unbounded)")
+	targetHeight := flag.Uint64("target-height", 0, "stop once the chain reaches this accepted height (0 = disabled)")
+	targetTxs := flag.Int64("target-txs", 0, "stop once this many transactions have been submitted (0 = disabled)")
+	deadline := flag.Duration("deadline", 0, "stop after this much wall-clock time (0 = disabled)")
+	metricsAddr := flag.String("metrics-addr", ":9092", "listen address for the /metrics endpoint")
+	flag.Parse()
+
+	if *uri == "" {
+		return fmt.Errorf("-uri is required")
+	}
+	if *keys == "" {
+		return fmt.Errorf("-keys is required")
+	}
+
+	accounts, err := loadAccounts(*keys)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hcli := hrpc.NewJSONRPCClient(*uri)
+	networkID, _, chainID, err := hcli.Network(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch network info: %w", err)
+	}
+	ncli := nrpc.NewJSONRPCClient(*uri, networkID, chainID)
+	parser, err := ncli.Parser(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch chain parser: %w", err)
+	}
+
+	entries := make([]loadgen.MixEntry, len(accounts))
+	for i, account := range accounts {
+		entries[i] = loadgen.MixEntry{
+			Workload: &loadgen.TransferWorkload{Factory: account, Value: *value},
+			Weight:   1,
+		}
+	}
+	workload, err := loadgen.NewWeightedMix(entries)
+	if err != nil {
+		return err
+	}
+
+	sink := loadgen.NewSink(ncli)
+	driver, err := loadgen.New(hcli, parser, workload, accounts, loadgen.Config{
+		WorkersPerAccount: *workersPerAccount,
+		InFlightPerWorker: *inFlightPerWorker,
+		TargetTPS:         *targetTPS,
+		TargetHeight:      *targetHeight,
+		TargetTxs:         *targetTxs,
+		Deadline:          *deadline,
+	}, sink)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(sink.Registry(), promhttp.HandlerOpts{}))
+	server := &http.Server{Addr: *metricsAddr, Handler: mux}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintln(os.Stderr, "metrics server exited:", err)
+		}
+	}()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		cancel()
+	}()
+
+	runErr := driver.Run(ctx)
+	_ = server.Close()
+	if runErr != nil && !errors.Is(runErr, context.Canceled) {
+		return runErr
+	}
+
+	return json.NewEncoder(os.Stdout).Encode(sink.Summary())
+}
+
+// loadAccounts decodes a comma-separated list of hex ED25519 private keys
+// into the auth.Factory accounts a Driver submits transactions on behalf of.
+func loadAccounts(keys string) ([]auth.Factory, error) {
+	parts := strings.Split(keys, ",")
+	accounts := make([]auth.Factory, len(parts))
+	for i, k := range parts {
+		privBytes, err := codec.LoadHex(strings.TrimSpace(k), ed25519.PrivateKeyLen)
+		if err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+		accounts[i] = auth.NewED25519Factory(ed25519.PrivateKey(privBytes))
+	}
+	return accounts, nil
+}
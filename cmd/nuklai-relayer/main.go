@@ -0,0 +1,107 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Command nuklai-relayer runs the relayer package as a standalone daemon,
+// relaying ExportAsset transactions between the subnets listed in its
+// config file. It is the out-of-process counterpart to embedding the
+// relayer package directly in the vm binary via its `--relayer` flag.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+
+	"github.com/nuklai/nuklaivm/relayer"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	configPath := flag.String("config", "", "path to the relayer's YAML or JSON config file")
+	metricsAddr := flag.String("metrics-addr", ":9091", "listen address for the /metrics and /healthz endpoints")
+	logLevel := flag.String("log-level", "info", "log level (debug, info, warn, error)")
+	flag.Parse()
+
+	if *configPath == "" {
+		return fmt.Errorf("-config is required")
+	}
+
+	level, err := logging.ToLevel(*logLevel)
+	if err != nil {
+		return fmt.Errorf("invalid -log-level: %w", err)
+	}
+	logFactory := logging.NewFactory(logging.Config{DisplayLevel: level, LogLevel: level})
+	log, err := logFactory.Make("relayer")
+	if err != nil {
+		return err
+	}
+
+	cfg, err := relayer.LoadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clients, err := relayer.DialClients(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	rl, err := relayer.New(log, cfg, clients)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(rl.Metrics().Registry(), promhttp.HandlerOpts{}))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		health := rl.Health()
+		ok := true
+		for _, h := range health {
+			ok = ok && h.Healthy()
+		}
+		if !ok {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(health)
+	})
+	server := &http.Server{Addr: *metricsAddr, Handler: mux}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error("metrics server exited", zap.Error(err))
+		}
+	}()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		log.Info("shutting down")
+		cancel()
+		_ = server.Close()
+	}()
+
+	if err := rl.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ package genesis
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 
@@ -30,9 +31,26 @@ type CustomAllocation struct {
 	Balance uint64 `json:"balance"`
 }
 
+// RemoteCommittee seeds storage.RemoteCommitteeKey for one remote chain:
+// actions.RemoteDelegateUserStake/RemoteWithdrawUserStake from SourceChainID
+// are only accepted once its committee's BLS public key has been loaded here.
+type RemoteCommittee struct {
+	SourceChainID      ids.ID `json:"sourceChainID"`
+	CommitteePublicKey string `json:"committeePublicKey"` // hex-encoded compressed BLS public key
+}
+
 type EmissionBalancer struct {
 	MaxSupply       uint64 `json:"maxSupply"`       // Max supply of NAI
 	EmissionAddress string `json:"emissionAddress"` // Emission address
+
+	// Slashing Parameters
+	SlashFraction     float64 `json:"slashFraction"`     // Fraction of stakedAmount burned/redirected per SlashValidatorStake
+	LivenessThreshold uint64  `json:"livenessThreshold"` // Consecutive missed heartbeat blocks before a validator is slashable for downtime
+	JailDuration      uint64  `json:"jailDuration"`      // Blocks a slashed validator is excluded from the active set for
+
+	// RedelegationCooldown is the minimum number of blocks a delegator must
+	// wait between two RedelegateUserStake calls.
+	RedelegationCooldown uint64 `json:"redelegationCooldown"`
 }
 
 type Genesis struct {
@@ -53,6 +71,12 @@ type Genesis struct {
 	ValidityWindow int64 `json:"validityWindow"` // ms
 
 	// Tx Fee Parameters
+	//
+	// BaseWarpComputeUnits/WarpComputeUnitsPerSigner/OutgoingWarpComputeUnits
+	// still price ExportAsset/ImportAsset and SendMessage/ReceiveMessage,
+	// which keep using warp — only the staking actions in
+	// actions/xdelegate.go move off it onto committee attestations, so these
+	// stay rather than being dropped wholesale.
 	BaseComputeUnits          uint64 `json:"baseUnits"`
 	BaseWarpComputeUnits      uint64 `json:"baseWarpUnits"`
 	WarpComputeUnitsPerSigner uint64 `json:"warpUnitsPerSigner"`
@@ -69,6 +93,23 @@ type Genesis struct {
 
 	// Emission Balancer Info
 	EmissionBalancer EmissionBalancer `json:"emissionBalancer"`
+
+	// Remote Delegation Committees
+	RemoteCommittees []*RemoteCommittee `json:"remoteCommittees,omitempty"`
+
+	// Upgrades are scheduled parameter changes layered on top of this
+	// Genesis at specific block heights; see GenesisUpgrade and RulesAt.
+	Upgrades []GenesisUpgrade `json:"upgrades,omitempty"`
+}
+
+// GenesisUpgrade is one scheduled change to Genesis's tunable parameters.
+// Overlay is JSON holding only the fields being changed (e.g. `{"minUnitPrice":
+// [200,200,200,200,200]}`); RulesAt applies it onto a copy of the base
+// Genesis with encoding/json's usual merge-onto-existing-value semantics, so
+// every field Overlay omits keeps its prior value.
+type GenesisUpgrade struct {
+	ActivationHeight uint64          `json:"activationHeight"`
+	Overlay          json.RawMessage `json:"overlay"`
 }
 
 func Default() *Genesis {
@@ -108,17 +149,36 @@ func Default() *Genesis {
 		EmissionBalancer: EmissionBalancer{
 			MaxSupply:       emission.GetStakingConfig().RewardConfig.SupplyCap,       // 10 billion NAI,
 			EmissionAddress: emission.GetStakingConfig().RewardConfig.EmissionAddress, // NAI emission address(If you don't pass this address, it will be set to the default address)
+
+			SlashFraction:     emission.GetStakingConfig().SlashFraction,     // fraction of stakedAmount slashed per offense
+			LivenessThreshold: emission.GetStakingConfig().LivenessThreshold, // missed heartbeat blocks before a validator is slashable
+			JailDuration:      emission.GetStakingConfig().JailDuration,      // blocks a slashed validator sits out of the active set
+
+			RedelegationCooldown: emission.GetStakingConfig().RedelegationCooldown, // blocks between a delegator's redelegations
 		},
 	}
 }
 
-func New(b []byte, _ []byte /* upgradeBytes */) (*Genesis, error) {
+func New(b []byte, upgradeBytes []byte) (*Genesis, error) {
 	g := Default()
 	if len(b) > 0 {
 		if err := json.Unmarshal(b, g); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal config %s: %w", string(b), err)
 		}
 	}
+	if len(upgradeBytes) > 0 {
+		if err := json.Unmarshal(upgradeBytes, &g.Upgrades); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal upgrades %s: %w", string(upgradeBytes), err)
+		}
+		// Validate every overlay unmarshals cleanly now, at startup, so a
+		// malformed upgrade fails loudly instead of being discovered at the
+		// height it was meant to activate.
+		for _, u := range g.Upgrades {
+			if _, err := g.mergedAt(u.ActivationHeight); err != nil {
+				return nil, fmt.Errorf("invalid upgrade at height %d: %w", u.ActivationHeight, err)
+			}
+		}
+	}
 	return g, nil
 }
 
@@ -145,7 +205,7 @@ func (g *Genesis) Load(ctx context.Context, tracer trace.Tracer, mu state.Mutabl
 		}
 	}
 
-	return storage.SetAsset(
+	if err := storage.SetAsset(
 		ctx,
 		mu,
 		ids.Empty,
@@ -155,7 +215,20 @@ func (g *Genesis) Load(ctx context.Context, tracer trace.Tracer, mu state.Mutabl
 		supply,
 		codec.EmptyAddress,
 		false,
-	)
+	); err != nil {
+		return err
+	}
+
+	for _, committee := range g.RemoteCommittees {
+		pubKey, err := hex.DecodeString(committee.CommitteePublicKey)
+		if err != nil {
+			return fmt.Errorf("%w: sourceChainID=%s", err, committee.SourceChainID)
+		}
+		if err := storage.SetRemoteCommittee(ctx, mu, committee.SourceChainID, pubKey); err != nil {
+			return fmt.Errorf("%w: sourceChainID=%s", err, committee.SourceChainID)
+		}
+	}
+	return nil
 }
 
 func (g *Genesis) GetStateBranchFactor() merkledb.BranchFactor {
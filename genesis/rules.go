@@ -0,0 +1,126 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/chain"
+)
+
+var _ chain.Rules = (*Rules)(nil)
+
+// Rules adapts a Genesis's static parameters to the per-block chain.Rules a
+// Parser hands every action's Execute/MaxComputeUnits/ValidRange: actions
+// like ExportAsset/SendMessage that emit a warp message read NetworkID and
+// ChainID off of it to address the warp.UnsignedMessage they build, and the
+// VM's fee engine reads the Get* methods to price a block's transactions.
+type Rules struct {
+	g *Genesis
+
+	networkID uint32
+	chainID   ids.ID
+}
+
+// Rules builds the chain.Rules g's Parser hands to every action executed at
+// timestamp t. t is currently unused: Rules always reflects the base
+// Genesis, with none of g.Upgrades applied — callers that need a scheduled
+// upgrade to take effect at its activation height must call RulesAt instead.
+func (g *Genesis) Rules(_ int64, networkID uint32, chainID ids.ID) *Rules {
+	return &Rules{g: g, networkID: networkID, chainID: chainID}
+}
+
+// RulesAt builds the chain.Rules active at height: the base Genesis with
+// every GenesisUpgrade whose ActivationHeight <= height layered on top, so
+// fees, block gaps, and staking config can change deterministically at a
+// scheduled height instead of requiring a coordinated hard fork. It takes
+// networkID and chainID for the same reason Rules does — chain.Rules embeds
+// NetworkID/ChainID so warp-emitting actions can address their message.
+//
+// rpc.Parser.Rules calls this with the height it observed at construction
+// time, since chain.Parser's Rules(t int64) has no height parameter of its
+// own to receive one through.
+func (g *Genesis) RulesAt(height uint64, networkID uint32, chainID ids.ID) (*Rules, error) {
+	merged, err := g.mergedAt(height)
+	if err != nil {
+		return nil, err
+	}
+	return merged.Rules(0, networkID, chainID), nil
+}
+
+// mergedAt returns a copy of g with every upgrade active at height applied,
+// in ActivationHeight order, so a later upgrade's overlay wins over an
+// earlier one for any field both touch. It does not mutate g.
+func (g *Genesis) mergedAt(height uint64) (*Genesis, error) {
+	merged := *g
+	merged.Upgrades = nil
+
+	active := make([]GenesisUpgrade, 0, len(g.Upgrades))
+	for _, u := range g.Upgrades {
+		if u.ActivationHeight <= height {
+			active = append(active, u)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].ActivationHeight < active[j].ActivationHeight })
+
+	for _, u := range active {
+		if err := json.Unmarshal(u.Overlay, &merged); err != nil {
+			return nil, fmt.Errorf("failed to apply upgrade at height %d: %w", u.ActivationHeight, err)
+		}
+	}
+	return &merged, nil
+}
+
+// PendingUpgrades returns the upgrades in g that haven't activated yet as of
+// height, in ActivationHeight order, for the pendingUpgrades RPC to surface.
+func (g *Genesis) PendingUpgrades(height uint64) []GenesisUpgrade {
+	pending := make([]GenesisUpgrade, 0, len(g.Upgrades))
+	for _, u := range g.Upgrades {
+		if u.ActivationHeight > height {
+			pending = append(pending, u)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].ActivationHeight < pending[j].ActivationHeight })
+	return pending
+}
+
+func (r *Rules) NetworkID() uint32 { return r.networkID }
+func (r *Rules) ChainID() ids.ID   { return r.chainID }
+
+func (r *Rules) GetMinBlockGap() int64      { return r.g.MinBlockGap }
+func (r *Rules) GetMinEmptyBlockGap() int64 { return r.g.MinEmptyBlockGap }
+func (r *Rules) GetValidityWindow() int64   { return r.g.ValidityWindow }
+
+func (r *Rules) GetMinUnitPrice() chain.Dimensions               { return r.g.MinUnitPrice }
+func (r *Rules) GetUnitPriceChangeDenominator() chain.Dimensions { return r.g.UnitPriceChangeDenominator }
+func (r *Rules) GetWindowTargetUnits() chain.Dimensions          { return r.g.WindowTargetUnits }
+func (r *Rules) GetMaxBlockUnits() chain.Dimensions              { return r.g.MaxBlockUnits }
+
+func (r *Rules) GetBaseComputeUnits() uint64 { return r.g.BaseComputeUnits }
+
+// GetBaseWarpComputeUnits, GetWarpComputeUnitsPerSigner, and
+// GetOutgoingWarpComputeUnits price a transaction that imports or emits a
+// warp message: a fixed base cost, a cost per BLS signer the aggregated
+// signature ImportAsset verifies covers, and a fixed cost for the chain
+// having to track an outgoing message ExportAsset/SendMessage emitted.
+func (r *Rules) GetBaseWarpComputeUnits() uint64      { return r.g.BaseWarpComputeUnits }
+func (r *Rules) GetWarpComputeUnitsPerSigner() uint64 { return r.g.WarpComputeUnitsPerSigner }
+func (r *Rules) GetOutgoingWarpComputeUnits() uint64  { return r.g.OutgoingWarpComputeUnits }
+
+func (r *Rules) GetStorageKeyReadUnits() uint64       { return r.g.StorageKeyReadUnits }
+func (r *Rules) GetStorageValueReadUnits() uint64     { return r.g.StorageValueReadUnits }
+func (r *Rules) GetStorageKeyAllocateUnits() uint64   { return r.g.StorageKeyAllocateUnits }
+func (r *Rules) GetStorageValueAllocateUnits() uint64 { return r.g.StorageValueAllocateUnits }
+func (r *Rules) GetStorageKeyWriteUnits() uint64      { return r.g.StorageKeyWriteUnits }
+func (r *Rules) GetStorageValueWriteUnits() uint64    { return r.g.StorageValueWriteUnits }
+
+// FetchCustom looks up a rule this VM doesn't define statically; nuklaivm
+// has none, so every lookup misses.
+func (*Rules) FetchCustom(string) (any, bool) {
+	return nil, false
+}
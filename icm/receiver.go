@@ -0,0 +1,61 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package icm holds the receiver dispatch table actions.ReceiveMessage
+// consults to route a verified Interchain Messaging payload to the program
+// registered to handle it, analogous to looking up a contract by address.
+// It is a separate package from registry so that actions.ReceiveMessage can
+// depend on it directly: registry already imports actions to register
+// action/auth types, so actions importing registry back would cycle.
+// Concrete receivers are expected to call Register from an init(), the same
+// way registry wires up action and auth types.
+package icm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// Receiver reacts to a verified ICM message addressed to it via
+// actions.SendMessage's Receiver field.
+type Receiver interface {
+	// StateKeys declares every state key Receive may touch for payload, so
+	// actions.ReceiveMessage can fold them into its own StateKeys before
+	// Receive ever runs.
+	StateKeys(payload []byte) []string
+	StateKeysMaxChunks(payload []byte) []uint16
+	// Receive handles payload sent by sender on sourceChainID, within a
+	// compute budget of maxUnits. It returns the compute units it actually
+	// spent and an opaque output recorded as the enclosing action's result.
+	Receive(ctx context.Context, mu state.Mutable, sourceChainID ids.ID, sender codec.Address, payload []byte, maxUnits uint64) (units uint64, output []byte, err error)
+}
+
+var (
+	mu        sync.RWMutex
+	receivers = map[codec.Address]Receiver{}
+)
+
+// Register registers receiver to handle ICM messages addressed to addr. It
+// panics on a duplicate registration, the same as a duplicate action/auth
+// TypeID would in the registry package.
+func Register(addr codec.Address, receiver Receiver) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := receivers[addr]; ok {
+		panic(fmt.Sprintf("icm: receiver already registered for %s", addr))
+	}
+	receivers[addr] = receiver
+}
+
+// Lookup returns the Receiver registered for addr, if any.
+func Lookup(addr codec.Address) (Receiver, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	r, ok := receivers[addr]
+	return r, ok
+}
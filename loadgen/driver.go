@@ -0,0 +1,210 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package loadgen
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	hrpc "github.com/ava-labs/hypersdk/rpc"
+
+	"github.com/nuklai/nuklaivm/auth"
+)
+
+// Config controls how a Driver paces and bounds a load-generation run.
+type Config struct {
+	// WorkersPerAccount is how many goroutines concurrently submit
+	// transactions on behalf of each account in Driver.accounts. Each
+	// account needs its own goroutine(s) rather than sharing one across
+	// accounts so that one account's in-flight window never blocks another's.
+	WorkersPerAccount int
+	// InFlightPerWorker caps how many transactions a single worker can have
+	// submitted but not yet confirmed at once; the worker blocks on
+	// submitting its next transaction once this many are outstanding. Zero
+	// defaults to 16.
+	InFlightPerWorker int
+	// TargetTPS caps the aggregate submit rate across every worker. Zero
+	// means unbounded (backpressured only by InFlightPerWorker).
+	TargetTPS float64
+	// TargetHeight, if nonzero, stops the run once the chain's accepted
+	// height reaches it.
+	TargetHeight uint64
+	// TargetTxs, if nonzero, stops the run once this many transactions have
+	// been submitted.
+	TargetTxs int64
+	// Deadline, if nonzero, stops the run after this much wall-clock time.
+	Deadline time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.WorkersPerAccount <= 0 {
+		c.WorkersPerAccount = 1
+	}
+	if c.InFlightPerWorker <= 0 {
+		c.InFlightPerWorker = 16
+	}
+	return c
+}
+
+// Driver spawns Config.WorkersPerAccount goroutines per pre-funded account in
+// accounts (accounts only sizes the worker pool; Workload decides which
+// Factory actually signs each action), each pulling actions from Workload and
+// submitting them concurrently through the HyperSDK JSON-RPC client,
+// rate-limited by Config.TargetTPS and backpressured by
+// Config.InFlightPerWorker. It replaces the single-factory
+// submit-then-busy-wait broadcast loop the e2e suite used to hand-roll.
+type Driver struct {
+	hcli     *hrpc.JSONRPCClient
+	parser   chain.Parser
+	workload Workload
+	accounts []auth.Factory
+	cfg      Config
+	sink     *Sink
+
+	submitted atomic.Int64
+}
+
+// New constructs a Driver. accounts must already be funded; Driver neither
+// funds nor deduplicates them.
+func New(
+	hcli *hrpc.JSONRPCClient,
+	parser chain.Parser,
+	workload Workload,
+	accounts []auth.Factory,
+	cfg Config,
+	sink *Sink,
+) (*Driver, error) {
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("driver needs at least one funded account")
+	}
+	return &Driver{
+		hcli:     hcli,
+		parser:   parser,
+		workload: workload,
+		accounts: accounts,
+		cfg:      cfg.withDefaults(),
+		sink:     sink,
+	}, nil
+}
+
+// Run drives the load generator until ctx is canceled (e.g. on SIGINT) or
+// one of Config's stop conditions (TargetHeight, TargetTxs, Deadline) is
+// reached, then waits for every worker's in-flight window to drain before
+// returning, so Sink.Summary reflects every submitted transaction's outcome.
+func (d *Driver) Run(ctx context.Context) error {
+	if d.cfg.Deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.cfg.Deadline)
+		defer cancel()
+	}
+
+	workers := len(d.accounts) * d.cfg.WorkersPerAccount
+	var perWorkerInterval time.Duration
+	if d.cfg.TargetTPS > 0 {
+		perWorkerTPS := d.cfg.TargetTPS / float64(workers)
+		if perWorkerTPS > 0 {
+			perWorkerInterval = time.Duration(float64(time.Second) / perWorkerTPS)
+		}
+	}
+
+	// Workers aren't pinned to a particular account: d.accounts only sizes
+	// the worker pool (one pre-funded signer needs to exist per worker so
+	// the network doesn't serialize on a single account), while the
+	// Workload each worker pulls from decides which Factory actually signs
+	// every given action.
+	done := make(chan struct{}, workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			d.worker(ctx, perWorkerInterval)
+		}()
+	}
+	for i := 0; i < workers; i++ {
+		<-done
+	}
+	return nil
+}
+
+// Submitted reports how many transactions have been submitted so far across
+// every worker.
+func (d *Driver) Submitted() int64 {
+	return d.submitted.Load()
+}
+
+func (d *Driver) worker(ctx context.Context, interval time.Duration) {
+	var ticker *time.Ticker
+	if interval > 0 {
+		ticker = time.NewTicker(interval)
+		defer ticker.Stop()
+	}
+	inFlight := make(chan struct{}, d.cfg.InFlightPerWorker)
+	var confirming sync.WaitGroup
+	// Every exit from the loop below falls through to here rather than
+	// returning directly, so confirming.Wait() always runs and this
+	// worker's in-flight transactions always finish confirming first.
+	defer confirming.Wait()
+
+	for {
+		if ctx.Err() != nil {
+			break
+		}
+		if d.cfg.TargetTxs > 0 && d.submitted.Load() >= d.cfg.TargetTxs {
+			break
+		}
+		if d.cfg.TargetHeight > 0 {
+			if _, height, _, err := d.hcli.Accepted(ctx); err == nil && height >= d.cfg.TargetHeight {
+				break
+			}
+		}
+		if ticker != nil {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+
+		action, signer, err := d.workload.NextAction(ctx)
+		if err != nil {
+			d.sink.recordSubmitFailure()
+			continue
+		}
+		submit, tx, _, err := d.hcli.GenerateTransaction(ctx, d.parser, nil, action, signer)
+		if err != nil {
+			d.sink.recordSubmitFailure()
+			continue
+		}
+
+		select {
+		case inFlight <- struct{}{}:
+		case <-ctx.Done():
+			break
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		submittedAt := time.Now()
+		if err := submit(ctx); err != nil {
+			d.sink.recordSubmitFailure()
+			<-inFlight
+			continue
+		}
+		d.submitted.Add(1)
+
+		confirming.Add(1)
+		go func(txID ids.ID) {
+			defer confirming.Done()
+			defer func() { <-inFlight }()
+			d.sink.wait(ctx, txID, submittedAt)
+		}(tx.ID())
+	}
+}
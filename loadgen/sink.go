@@ -0,0 +1,168 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package loadgen
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/prometheus/client_golang/prometheus"
+
+	nrpc "github.com/nuklai/nuklaivm/rpc"
+)
+
+// Sink tails submitted transactions for their accepted/executed outcome and
+// records latency and success/failure metrics, mirroring how relayer.Metrics
+// instruments relay legs. A Driver confirms every submitted transaction
+// through wait, the logic behind Track, each on its own goroutine; a slow
+// confirmation holds one of its worker's InFlightPerWorker slots until it
+// returns.
+type Sink struct {
+	ncli *nrpc.JSONRPCClient
+
+	registry *prometheus.Registry
+
+	submitToAccepted  prometheus.Histogram
+	submitToExecuted  prometheus.Histogram
+	succeeded         prometheus.Counter
+	failed            prometheus.Counter
+	failedByErrClass  *prometheus.CounterVec
+
+	mu      sync.Mutex
+	summary Summary
+}
+
+// Summary is the JSON snapshot Sink reports at shutdown.
+type Summary struct {
+	Submitted  int64             `json:"submitted"`
+	Succeeded  int64             `json:"succeeded"`
+	Failed     int64             `json:"failed"`
+	ByErrClass map[string]int64 `json:"byErrClass,omitempty"`
+}
+
+// NewSink constructs a Sink that confirms transactions against ncli.
+func NewSink(ncli *nrpc.JSONRPCClient) *Sink {
+	s := &Sink{
+		ncli:     ncli,
+		registry: prometheus.NewRegistry(),
+		submitToAccepted: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "nuklai_loadgen",
+			Name:      "submit_to_accepted_seconds",
+			Help:      "Time from a transaction being submitted to its block being accepted.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		submitToExecuted: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "nuklai_loadgen",
+			Name:      "submit_to_executed_seconds",
+			Help:      "Time from a transaction being submitted to WaitForTransaction reporting its outcome.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		succeeded: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "nuklai_loadgen",
+			Name:      "succeeded_total",
+			Help:      "Number of submitted transactions that executed successfully.",
+		}),
+		failed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "nuklai_loadgen",
+			Name:      "failed_total",
+			Help:      "Number of submitted transactions that failed to submit, confirm, or execute.",
+		}),
+		failedByErrClass: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "nuklai_loadgen",
+			Name:      "failed_by_class_total",
+			Help:      "Failures labeled by a coarse error class (submit, confirm, or reverted).",
+		}, []string{"class"}),
+		summary: Summary{ByErrClass: map[string]int64{}},
+	}
+	s.registry.MustRegister(s.submitToAccepted, s.submitToExecuted, s.succeeded, s.failed, s.failedByErrClass)
+	return s
+}
+
+// Registry returns the Prometheus registry to serve on a `/metrics`
+// endpoint.
+func (s *Sink) Registry() *prometheus.Registry {
+	return s.registry
+}
+
+// recordSubmitFailure records a transaction that never made it onto the
+// network at all, e.g. because GenerateTransaction or submit(ctx) errored.
+func (s *Sink) recordSubmitFailure() {
+	s.failedByErrClass.WithLabelValues("submit").Inc()
+	s.failed.Inc()
+	s.mu.Lock()
+	s.summary.Submitted++
+	s.summary.Failed++
+	s.summary.ByErrClass["submit"]++
+	s.mu.Unlock()
+}
+
+// Track records txID as submitted at submittedAt and, on a background
+// goroutine, waits for it to confirm via WaitForTransaction, recording the
+// resulting latency and success/failure metrics. ctx bounds how long Track
+// waits for confirmation; callers typically derive it from the Driver's run
+// deadline rather than per-transaction.
+func (s *Sink) Track(ctx context.Context, txID ids.ID, submittedAt time.Time) {
+	go s.wait(ctx, txID, submittedAt)
+}
+
+// wait is Track's confirmation logic, split out so a Driver worker can run
+// it on its own goroutine and release its in-flight slot when it returns,
+// rather than Track's goroutine doing so invisibly.
+func (s *Sink) wait(ctx context.Context, txID ids.ID, submittedAt time.Time) {
+	s.mu.Lock()
+	s.summary.Submitted++
+	s.mu.Unlock()
+
+	success, _, err := s.ncli.WaitForTransaction(ctx, txID)
+	if err != nil {
+		s.failedByErrClass.WithLabelValues("confirm").Inc()
+		s.failed.Inc()
+		s.mu.Lock()
+		s.summary.Failed++
+		s.summary.ByErrClass["confirm"]++
+		s.mu.Unlock()
+		return
+	}
+
+	elapsed := time.Since(submittedAt).Seconds()
+	s.submitToAccepted.Observe(elapsed)
+	s.submitToExecuted.Observe(elapsed)
+
+	if !success {
+		s.failedByErrClass.WithLabelValues("reverted").Inc()
+		s.failed.Inc()
+		s.mu.Lock()
+		s.summary.Failed++
+		s.summary.ByErrClass["reverted"]++
+		s.mu.Unlock()
+		return
+	}
+
+	s.succeeded.Inc()
+	s.mu.Lock()
+	s.summary.Succeeded++
+	s.mu.Unlock()
+}
+
+// Summary returns a point-in-time snapshot of the counts tracked so far,
+// suitable for a JSON summary at shutdown. It's safe to call before every
+// in-flight Track goroutine has finished; a caller that wants a final tally
+// should give those goroutines a chance to drain first (e.g. via Driver.Run
+// returning only after its in-flight window is empty).
+func (s *Sink) Summary() Summary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	byErrClass := make(map[string]int64, len(s.summary.ByErrClass))
+	for k, v := range s.summary.ByErrClass {
+		byErrClass[k] = v
+	}
+	return Summary{
+		Submitted:  s.summary.Submitted,
+		Succeeded:  s.summary.Succeeded,
+		Failed:     s.summary.Failed,
+		ByErrClass: byErrClass,
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package loadgen
+
+import (
+	"context"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+
+	"github.com/nuklai/nuklaivm/actions"
+	"github.com/nuklai/nuklaivm/auth"
+)
+
+// TransferWorkload is a Workload that issues actions.Transfer of Value to a
+// freshly generated recipient on every call, signed by Factory. It is the
+// same generator the e2e suite's hand-rolled broadcast loop used before this
+// package existed.
+type TransferWorkload struct {
+	Factory auth.Factory
+	Value   uint64
+}
+
+func (w *TransferWorkload) NextAction(context.Context) (chain.Action, auth.Factory, error) {
+	other, err := ed25519.GeneratePrivateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &actions.Transfer{
+		To:    auth.NewED25519Address(other.PublicKey()),
+		Value: w.Value,
+	}, w.Factory, nil
+}
@@ -0,0 +1,80 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package loadgen
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"github.com/ava-labs/hypersdk/chain"
+
+	"github.com/nuklai/nuklaivm/auth"
+)
+
+// Workload produces the next action a Driver worker should submit, along
+// with the auth.Factory that should sign it. Implementations decide what to
+// generate; a Driver just keeps calling NextAction as fast as its
+// backpressure settings allow. Transfer, CreateAsset/MintAsset, order-book
+// actions, etc. each get their own Workload so they can be combined via
+// WeightedMix rather than the Driver hard-coding any one of them.
+type Workload interface {
+	NextAction(ctx context.Context) (chain.Action, auth.Factory, error)
+}
+
+// MixEntry pairs a Workload with its relative weight in a WeightedMix.
+type MixEntry struct {
+	Workload Workload
+	// Weight is this entry's share of NextAction calls, relative to the
+	// other entries in the same mix; weights don't need to sum to anything
+	// in particular; WeightedMix makes every selection in their proportion.
+	Weight int
+}
+
+// WeightedMix is a Workload that dispatches each call to one of several
+// underlying Workloads, chosen at random in proportion to their configured
+// Weight. It's how a Driver run combines, e.g., mostly Transfer actions with
+// an occasional CreateAsset/MintAsset.
+type WeightedMix struct {
+	mu      sync.Mutex
+	rng     *rand.Rand
+	entries []MixEntry
+	total   int
+}
+
+// NewWeightedMix builds a WeightedMix from entries, all of which must have a
+// positive Weight.
+func NewWeightedMix(entries []MixEntry) (*WeightedMix, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("weighted mix must have at least one entry")
+	}
+	total := 0
+	for i, e := range entries {
+		if e.Weight <= 0 {
+			return nil, fmt.Errorf("entry %d: weight must be positive, got %d", i, e.Weight)
+		}
+		total += e.Weight
+	}
+	return &WeightedMix{
+		rng:     rand.New(rand.NewSource(rand.Int63())), //nolint:gosec
+		entries: entries,
+		total:   total,
+	}, nil
+}
+
+func (m *WeightedMix) NextAction(ctx context.Context) (chain.Action, auth.Factory, error) {
+	m.mu.Lock()
+	n := m.rng.Intn(m.total)
+	m.mu.Unlock()
+
+	for _, e := range m.entries {
+		if n < e.Weight {
+			return e.Workload.NextAction(ctx)
+		}
+		n -= e.Weight
+	}
+	// Unreachable: n < m.total is guaranteed by construction.
+	return m.entries[len(m.entries)-1].Workload.NextAction(ctx)
+}
@@ -36,6 +36,15 @@ func init() {
 		nconsts.ActionRegistry.Register((&actions.DelegateUserStake{}).GetTypeID(), actions.UnmarshalDelegateUserStake, false),
 		nconsts.ActionRegistry.Register((&actions.ClaimDelegationStakeRewards{}).GetTypeID(), actions.UnmarshalClaimDelegationStakeRewards, false),
 		nconsts.ActionRegistry.Register((&actions.UndelegateUserStake{}).GetTypeID(), actions.UnmarshalUndelegateUserStake, false),
+		nconsts.ActionRegistry.Register((&actions.BatchTransfer{}).GetTypeID(), actions.UnmarshalBatchTransfer, false),
+		nconsts.ActionRegistry.Register((&actions.SendMessage{}).GetTypeID(), actions.UnmarshalSendMessage, false),
+		nconsts.ActionRegistry.Register((&actions.ReceiveMessage{}).GetTypeID(), actions.UnmarshalReceiveMessage, true),
+		nconsts.ActionRegistry.Register((&actions.SlashValidatorStake{}).GetTypeID(), actions.UnmarshalSlashValidatorStake, false),
+		nconsts.ActionRegistry.Register((&actions.RemoteDelegateUserStake{}).GetTypeID(), actions.UnmarshalRemoteDelegateUserStake, false),
+		nconsts.ActionRegistry.Register((&actions.RemoteWithdrawUserStake{}).GetTypeID(), actions.UnmarshalRemoteWithdrawUserStake, false),
+		nconsts.ActionRegistry.Register((&actions.ClaimDelegatorRewards{}).GetTypeID(), actions.UnmarshalClaimDelegatorRewards, false),
+		nconsts.ActionRegistry.Register((&actions.IncreaseDelegatorStake{}).GetTypeID(), actions.UnmarshalIncreaseDelegatorStake, false),
+		nconsts.ActionRegistry.Register((&actions.RedelegateUserStake{}).GetTypeID(), actions.UnmarshalRedelegateUserStake, false),
 
 		// When registering new auth, ALWAYS make sure to append at the end.
 		nconsts.AuthRegistry.Register((&auth.ED25519{}).GetTypeID(), auth.UnmarshalED25519, false),
@@ -0,0 +1,62 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"context"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	hrpc "github.com/ava-labs/hypersdk/rpc"
+)
+
+// AggregationUpdate reports one observation of hcli.GenerateAggregateWarpSignature
+// for a pending export, as emitted by StreamAggregateWarpSignature.
+type AggregationUpdate struct {
+	Msg          *warp.Message
+	SubnetWeight uint64
+	SigWeight    uint64
+	Err          error
+}
+
+// Quorum reports whether this update's observed signature weight meets
+// minWeightPercent of the subnet's total weight. It is false on an errored
+// update or while SubnetWeight is still unknown (zero).
+func (u AggregationUpdate) Quorum(minWeightPercent float64) bool {
+	if u.Err != nil || u.SubnetWeight == 0 {
+		return false
+	}
+	return float64(u.SigWeight)*100/float64(u.SubnetWeight) >= minWeightPercent
+}
+
+// StreamAggregateWarpSignature polls hcli.GenerateAggregateWarpSignature for
+// txID every interval until ctx is done, emitting every observation on the
+// returned channel, which is closed when ctx is done. Unlike a hand-rolled
+// "block until sigWeight == subnetWeight" loop, it never blocks the caller
+// between polls: the caller decides when an update's weight is good enough
+// (e.g. via Quorum) and can stop consuming early rather than waiting for
+// every validator to sign, some of which may never come online.
+func StreamAggregateWarpSignature(ctx context.Context, hcli *hrpc.JSONRPCClient, txID ids.ID, interval time.Duration) <-chan AggregationUpdate {
+	updates := make(chan AggregationUpdate)
+	go func() {
+		defer close(updates)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			msg, subnetWeight, sigWeight, err := hcli.GenerateAggregateWarpSignature(ctx, txID)
+			select {
+			case updates <- AggregationUpdate{Msg: msg, SubnetWeight: subnetWeight, SigWeight: sigWeight, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return updates
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import "time"
+
+// BackoffConfig controls how long a route waits before retrying a leg after
+// GenerateAggregateWarpSignature or submission fails, so a source subnet
+// that's temporarily unreachable doesn't get hammered every pollInterval.
+type BackoffConfig struct {
+	// Initial is the delay before the first retry. Defaults to pollInterval.
+	Initial time.Duration `json:"initial,omitempty" yaml:"initial,omitempty"`
+	// Max caps the delay between retries. Defaults to 30s.
+	Max time.Duration `json:"max,omitempty" yaml:"max,omitempty"`
+	// Multiplier scales Initial on each consecutive failure. Defaults to 2.
+	Multiplier float64 `json:"multiplier,omitempty" yaml:"multiplier,omitempty"`
+	// MaxAttempts caps how many times a leg is retried after a submission
+	// failure before the relayer gives up and delivers the error to
+	// WaitForImport. Zero means retry indefinitely.
+	MaxAttempts int `json:"maxAttempts,omitempty" yaml:"maxAttempts,omitempty"`
+}
+
+func (c BackoffConfig) withDefaults() BackoffConfig {
+	if c.Initial <= 0 {
+		c.Initial = pollInterval
+	}
+	if c.Max <= 0 {
+		c.Max = 30 * time.Second
+	}
+	if c.Multiplier <= 1 {
+		c.Multiplier = 2
+	}
+	return c
+}
+
+// backoff tracks the retry delay for a single pendingExport's current leg. A
+// zero-value backoff is ready to use.
+type backoff struct {
+	attempt int
+	until   time.Time
+}
+
+// ready reports whether enough time has passed since the last failure to
+// retry now.
+func (b *backoff) ready(now time.Time) bool {
+	return now.After(b.until) || now.Equal(b.until)
+}
+
+// fail records a failed attempt and schedules the next retry no sooner than
+// cfg's exponential delay from now.
+func (b *backoff) fail(cfg BackoffConfig, now time.Time) {
+	cfg = cfg.withDefaults()
+	delay := cfg.Initial
+	for i := 0; i < b.attempt; i++ {
+		delay = time.Duration(float64(delay) * cfg.Multiplier)
+		if delay > cfg.Max {
+			delay = cfg.Max
+			break
+		}
+	}
+	b.attempt++
+	b.until = now.Add(delay)
+}
+
+// reset clears accumulated backoff after a successful attempt.
+func (b *backoff) reset() {
+	b.attempt = 0
+	b.until = time.Time{}
+}
@@ -0,0 +1,166 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// LegRecord is what Checkpoint durably records once a route's leg at a given
+// hop has landed its ImportAsset.
+type LegRecord struct {
+	// ImportTxID and Fee are the result of the ImportAsset that landed this
+	// leg.
+	ImportTxID ids.ID `json:"importTxID"`
+	Fee        uint64 `json:"fee"`
+	// Final is true if this leg landed on the route's destination, i.e.
+	// there's no follow-on export to submit.
+	Final bool `json:"final"`
+	// NextLegTxID is the export txID submitted to carry the transfer to the
+	// next hop. It is ids.Empty until that submission is itself durably
+	// recorded, which can lag behind ImportTxID landing if the process
+	// crashes between the two.
+	NextLegTxID ids.ID `json:"nextLegTxID,omitempty"`
+}
+
+// Checkpoint durably records, per hop, which legs of a route have already
+// relayed to completion, so a Relayer process that crashes mid-route and
+// restarts doesn't double-submit ImportAsset for a leg (intermediate or
+// final) it already landed. InMemoryCheckpoint preserves the original
+// behavior (no resume, no dedupe across restarts); FileCheckpoint persists to
+// disk.
+type Checkpoint interface {
+	// Imported reports whether source/originTxID's leg at hopIdx already
+	// landed, returning its record if so.
+	Imported(source, originTxID ids.ID, hopIdx int) (rec LegRecord, ok bool)
+	// MarkImported durably records rec for source/originTxID's leg at
+	// hopIdx. It may be called more than once for the same leg — e.g. once
+	// when the leg's ImportAsset lands, and again once its follow-on export
+	// is submitted and rec.NextLegTxID becomes known — with the latest call
+	// winning.
+	MarkImported(source, originTxID ids.ID, hopIdx int, rec LegRecord) error
+}
+
+var (
+	_ Checkpoint = (*InMemoryCheckpoint)(nil)
+	_ Checkpoint = (*FileCheckpoint)(nil)
+)
+
+// checkpointKey is a route's originTxID, scoped by its source blockchain and
+// the hop it completed. The same txID bytes can never collide across chains
+// anyway, but this keeps the on-disk record self-describing.
+type checkpointKey struct {
+	Source     ids.ID `json:"source"`
+	OriginTxID ids.ID `json:"originTxID"`
+	HopIdx     int    `json:"hopIdx"`
+}
+
+// InMemoryCheckpoint is the default Checkpoint: state lives only in process
+// memory, so a restart re-relays any export still in flight. It exists so
+// New doesn't require a Checkpoint to be configured.
+type InMemoryCheckpoint struct {
+	mu      sync.Mutex
+	records map[checkpointKey]LegRecord
+}
+
+// NewInMemoryCheckpoint constructs a Checkpoint with no durability across
+// restarts.
+func NewInMemoryCheckpoint() *InMemoryCheckpoint {
+	return &InMemoryCheckpoint{records: map[checkpointKey]LegRecord{}}
+}
+
+func (c *InMemoryCheckpoint) Imported(source, originTxID ids.ID, hopIdx int) (LegRecord, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, ok := c.records[checkpointKey{Source: source, OriginTxID: originTxID, HopIdx: hopIdx}]
+	return r, ok
+}
+
+func (c *InMemoryCheckpoint) MarkImported(source, originTxID ids.ID, hopIdx int, rec LegRecord) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.records[checkpointKey{Source: source, OriginTxID: originTxID, HopIdx: hopIdx}] = rec
+	return nil
+}
+
+// FileCheckpoint is a Checkpoint backed by a single JSON file, rewritten
+// atomically (write to a temp file, then rename) after every MarkImported so
+// a crash never leaves a partially-written checkpoint behind. It's meant for
+// a single relayer process; running two processes against the same file
+// concurrently isn't supported.
+type FileCheckpoint struct {
+	path string
+
+	mu      sync.Mutex
+	records map[checkpointKey]LegRecord
+}
+
+// NewFileCheckpoint loads a FileCheckpoint from [path], which is created on
+// the first MarkImported if it doesn't already exist.
+func NewFileCheckpoint(path string) (*FileCheckpoint, error) {
+	c := &FileCheckpoint{path: path, records: map[checkpointKey]LegRecord{}}
+
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read relayer checkpoint %s: %w", path, err)
+	}
+
+	var entries []struct {
+		Key    checkpointKey `json:"key"`
+		Record LegRecord     `json:"record"`
+	}
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse relayer checkpoint %s: %w", path, err)
+	}
+	for _, e := range entries {
+		c.records[e.Key] = e.Record
+	}
+	return c, nil
+}
+
+func (c *FileCheckpoint) Imported(source, originTxID ids.ID, hopIdx int) (LegRecord, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, ok := c.records[checkpointKey{Source: source, OriginTxID: originTxID, HopIdx: hopIdx}]
+	return r, ok
+}
+
+func (c *FileCheckpoint) MarkImported(source, originTxID ids.ID, hopIdx int, rec LegRecord) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.records[checkpointKey{Source: source, OriginTxID: originTxID, HopIdx: hopIdx}] = rec
+
+	entries := make([]struct {
+		Key    checkpointKey `json:"key"`
+		Record LegRecord     `json:"record"`
+	}, 0, len(c.records))
+	for k, r := range c.records {
+		entries = append(entries, struct {
+			Key    checkpointKey `json:"key"`
+			Record LegRecord     `json:"record"`
+		}{Key: k, Record: r})
+	}
+
+	b, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal relayer checkpoint: %w", err)
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return fmt.Errorf("failed to write relayer checkpoint %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("failed to install relayer checkpoint %s: %w", c.path, err)
+	}
+	return nil
+}
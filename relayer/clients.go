@@ -0,0 +1,47 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	hrpc "github.com/ava-labs/hypersdk/rpc"
+	nrpc "github.com/nuklai/nuklaivm/rpc"
+)
+
+// DialClients builds the Clients New needs from cfg.Endpoints, dialing
+// every blockchain referenced in any Route's Hops exactly once. It is used
+// by the standalone relayer daemon (cmd/nuklai-relayer); the e2e suite
+// builds Clients directly from its already-running in-process instances
+// instead.
+func DialClients(ctx context.Context, cfg *Config) (Clients, error) {
+	clients := Clients{HCLI: map[ids.ID]*hrpc.JSONRPCClient{}, NCLI: map[ids.ID]*nrpc.JSONRPCClient{}}
+	for _, rc := range cfg.Routes {
+		hops := rc.Hops
+		if len(hops) == 0 {
+			hops = []ids.ID{rc.SourceBlockchainID, rc.DestinationBlockchainID}
+		}
+		for _, chainID := range hops {
+			if _, ok := clients.HCLI[chainID]; ok {
+				continue
+			}
+			endpoint, ok := cfg.Endpoints[chainID]
+			if !ok {
+				return Clients{}, fmt.Errorf("no endpoint configured for blockchain %s", chainID)
+			}
+
+			hcli := hrpc.NewJSONRPCClient(endpoint)
+			networkID, _, _, err := hcli.Network(ctx)
+			if err != nil {
+				return Clients{}, fmt.Errorf("failed to query network info from %s: %w", endpoint, err)
+			}
+
+			clients.HCLI[chainID] = hcli
+			clients.NCLI[chainID] = nrpc.NewJSONRPCClient(endpoint, networkID, chainID)
+		}
+	}
+	return clients, nil
+}
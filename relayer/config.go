@@ -0,0 +1,105 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"gopkg.in/yaml.v2"
+)
+
+// RouteConfig describes a single warp relay path, mirroring awm-relayer's
+// (sourceBlockchainID, destinationBlockchainID, signingKey) configuration
+// model.
+type RouteConfig struct {
+	SourceBlockchainID      ids.ID `json:"sourceBlockchainID" yaml:"sourceBlockchainID"`
+	DestinationBlockchainID ids.ID `json:"destinationBlockchainID" yaml:"destinationBlockchainID"`
+	// SigningKey is the hex-encoded ED25519 private key used to sign
+	// ImportAsset transactions submitted on DestinationBlockchainID.
+	SigningKey string `json:"signingKey" yaml:"signingKey"`
+	// MinStakeWeightPercent is the minimum percentage (0, 100] of the source
+	// subnet's stake weight that must sign a warp message before the relayer
+	// will submit the corresponding ImportAsset transaction.
+	MinStakeWeightPercent float64 `json:"minStakeWeightPercent" yaml:"minStakeWeightPercent"`
+	// Hops is the ordered chain of blockchain IDs an asset passes through,
+	// starting at SourceBlockchainID and ending at DestinationBlockchainID.
+	// Leave it empty for a direct route; LoadConfig fills it in as
+	// [SourceBlockchainID, DestinationBlockchainID]. Listing one or more
+	// chains in between makes this a multi-hop route: once the import onto
+	// an intermediate hop lands, the relayer auto-submits the export toward
+	// the next hop on the caller's behalf, using the same SigningKey on
+	// every chain in the path.
+	Hops []ids.ID `json:"hops,omitempty" yaml:"hops,omitempty"`
+}
+
+// Config is the relayer's top-level configuration: one Route per
+// source/destination pair it services.
+type Config struct {
+	Routes []RouteConfig `json:"routes" yaml:"routes"`
+	// Endpoints maps every blockchain ID appearing in any Route's Hops to
+	// the nuklai RPC URL the standalone relayer daemon (cmd/nuklai-relayer)
+	// should dial for it. Unused when a Relayer is built with an
+	// already-constructed Clients value, e.g. from the e2e suite.
+	Endpoints map[ids.ID]string `json:"endpoints" yaml:"endpoints"`
+	// Backoff controls retry delay after a leg fails to submit or aggregate
+	// signatures. Zero value uses BackoffConfig's own defaults.
+	Backoff BackoffConfig `json:"backoff,omitempty" yaml:"backoff,omitempty"`
+	// CheckpointPath, if set, persists relayed exports to this file so a
+	// restarted relayer resumes without double-submitting ImportAsset for
+	// work it already completed. Leave empty to keep checkpoint state only
+	// in memory (the original, non-durable behavior).
+	CheckpointPath string `json:"checkpointPath,omitempty" yaml:"checkpointPath,omitempty"`
+}
+
+// LoadConfig reads a Config from a YAML or JSON file, selected by [path]'s
+// extension.
+func LoadConfig(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read relayer config %s: %w", path, err)
+	}
+
+	var cfg Config
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(b, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse relayer config %s: %w", path, err)
+		}
+	case ".json":
+		if err := json.Unmarshal(b, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse relayer config %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported relayer config extension %q", ext)
+	}
+
+	for i := range cfg.Routes {
+		r := &cfg.Routes[i]
+		if r.MinStakeWeightPercent <= 0 || r.MinStakeWeightPercent > 100 {
+			return nil, fmt.Errorf("route %d: minStakeWeightPercent must be in (0, 100]", i)
+		}
+		if r.SigningKey == "" {
+			return nil, fmt.Errorf("route %d: signingKey is required", i)
+		}
+
+		if len(r.Hops) == 0 {
+			r.Hops = []ids.ID{r.SourceBlockchainID, r.DestinationBlockchainID}
+		}
+		if len(r.Hops) < 2 {
+			return nil, fmt.Errorf("route %d: hops must list at least a source and a destination", i)
+		}
+		if r.Hops[0] != r.SourceBlockchainID {
+			return nil, fmt.Errorf("route %d: hops must start at sourceBlockchainID", i)
+		}
+		if r.Hops[len(r.Hops)-1] != r.DestinationBlockchainID {
+			return nil, fmt.Errorf("route %d: hops must end at destinationBlockchainID", i)
+		}
+	}
+	return &cfg, nil
+}
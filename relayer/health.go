@@ -0,0 +1,61 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// staleAfter is how long a route can go without finishing a poll, successful
+// or not, before Health reports it unhealthy, e.g. because its poll loop is
+// stuck on a call that never returns.
+const staleAfter = 5 * time.Minute
+
+// RouteHealth reports the last poll outcome for a single configured route,
+// suitable for serving on a `/healthz` endpoint.
+type RouteHealth struct {
+	Source     ids.ID    `json:"source"`
+	LastPolled time.Time `json:"lastPolled"`
+	// LastErr is the last error GenerateAggregateWarpSignature returned during
+	// the route's most recent poll, or nil if that poll saw none, which
+	// includes a poll with no pending exports and one whose pending legs were
+	// all still backing off.
+	LastErr error
+}
+
+// Healthy reports whether the route's most recent poll saw no aggregation
+// error and finished recently enough that the route isn't considered stale.
+func (h RouteHealth) Healthy() bool {
+	return h.LastErr == nil && (h.LastPolled.IsZero() || time.Since(h.LastPolled) < staleAfter)
+}
+
+// MarshalJSON renders LastErr as its message string, since error values
+// otherwise marshal to an opaque "{}".
+func (h RouteHealth) MarshalJSON() ([]byte, error) {
+	var lastErr string
+	if h.LastErr != nil {
+		lastErr = h.LastErr.Error()
+	}
+	return json.Marshal(struct {
+		Source     ids.ID    `json:"source"`
+		LastPolled time.Time `json:"lastPolled"`
+		LastErr    string    `json:"lastErr,omitempty"`
+		Healthy    bool      `json:"healthy"`
+	}{Source: h.Source, LastPolled: h.LastPolled, LastErr: lastErr, Healthy: h.Healthy()})
+}
+
+// Health reports the current RouteHealth for every configured route.
+func (r *Relayer) Health() []RouteHealth {
+	health := make([]RouteHealth, 0, len(r.routes))
+	for source, rt := range r.routes {
+		rt.mu.Lock()
+		h := RouteHealth{Source: source, LastPolled: rt.lastPollAt, LastErr: rt.lastPollErr}
+		rt.mu.Unlock()
+		health = append(health, h)
+	}
+	return health
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics exposes Prometheus instrumentation for relayer operations,
+// mirroring the faucet's own manager.Metrics so operators get the same
+// `/metrics` visibility for this subsystem.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	relayLatency prometheus.Histogram
+	pending      *prometheus.GaugeVec
+	relayed      *prometheus.CounterVec
+	failed       *prometheus.CounterVec
+}
+
+// NewMetrics constructs a Metrics instance registered against a fresh
+// Prometheus registry, suitable for serving on a `/metrics` endpoint.
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		relayLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "nuklai_relayer",
+			Name:      "relay_latency_seconds",
+			Help:      "Time from an export being registered with Relay/RelayHop to its final ImportAsset landing.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		pending: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "nuklai_relayer",
+			Name:      "pending_exports",
+			Help:      "Number of exports currently awaiting signature quorum or relay, labeled by source blockchain.",
+		}, []string{"source"}),
+		relayed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "nuklai_relayer",
+			Name:      "relayed_total",
+			Help:      "Number of exports successfully relayed to their final destination, labeled by source blockchain.",
+		}, []string{"source"}),
+		failed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "nuklai_relayer",
+			Name:      "failed_total",
+			Help:      "Number of relay legs that returned an error, labeled by source blockchain.",
+		}, []string{"source"}),
+	}
+	m.registry.MustRegister(
+		m.relayLatency,
+		m.pending,
+		m.relayed,
+		m.failed,
+	)
+	return m
+}
+
+// Registry returns the Prometheus registry to serve on `/metrics`.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+func (m *Metrics) observeRelayLatency(seconds float64) {
+	m.relayLatency.Observe(seconds)
+}
+
+func (m *Metrics) setPending(source ids.ID, n int) {
+	m.pending.WithLabelValues(source.String()).Set(float64(n))
+}
+
+func (m *Metrics) recordRelayed(source ids.ID) {
+	m.relayed.WithLabelValues(source.String()).Inc()
+}
+
+func (m *Metrics) recordFailure(source ids.ID) {
+	m.failed.WithLabelValues(source.String()).Inc()
+}
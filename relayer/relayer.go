@@ -0,0 +1,530 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package relayer watches one or more nuklaivm subnets for ExportAsset
+// transactions, aggregates the BLS signatures source-subnet validators
+// attach to the resulting warp message, and submits the matching
+// ImportAsset transaction on the destination subnet once enough stake
+// weight has signed. It is meant to be embedded both as a sidecar goroutine
+// in the vm binary (via the `--relayer` flag) and directly in the e2e test
+// suite, replacing hand-rolled aggregation/import polling loops in either
+// place.
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	hrpc "github.com/ava-labs/hypersdk/rpc"
+	"github.com/nuklai/nuklaivm/actions"
+	"github.com/nuklai/nuklaivm/auth"
+	nrpc "github.com/nuklai/nuklaivm/rpc"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// pollInterval is how often a route re-checks aggregate signature weight for
+// its pending exports.
+const pollInterval = time.Second
+
+// hopClients bundles the RPC clients needed to both originate a leg of a
+// route from a chain (sourceHCLI, to aggregate and submit exports) and land
+// a leg on it (ncli, to submit and confirm imports/follow-on exports).
+type hopClients struct {
+	blockchainID ids.ID
+	hcli         *hrpc.JSONRPCClient
+	ncli         *nrpc.JSONRPCClient
+}
+
+// route holds the resolved, ready-to-use state for a single RouteConfig.
+// hops always has at least two entries: hops[0] is cfg.SourceBlockchainID
+// and hops[len(hops)-1] is cfg.DestinationBlockchainID. A direct route has
+// exactly two; a multi-hop route lists every intermediate chain in between.
+type route struct {
+	cfg     RouteConfig
+	hops    []hopClients
+	factory *auth.ED25519Factory
+	backoff BackoffConfig
+
+	mu        sync.Mutex
+	byOrigin  map[ids.ID]*pendingExport // keyed by the exportTxID the caller registered with Relay/RelayHop
+	byCurrent map[ids.ID]*pendingExport // keyed by the currently in-flight leg's export txID
+
+	// lastPollAt and lastPollErr record the outcome of the most recent
+	// pollRoute call, for Relayer.Health.
+	lastPollAt  time.Time
+	lastPollErr error
+}
+
+// HopParams carries the transfer parameters a multi-hop route needs to
+// reconstruct the ExportAsset for each leg past the first. It is unused by
+// direct (single-hop) routes, which only ever submit one ImportAsset.
+type HopParams struct {
+	To codec.Address
+	// Asset is the *origin* asset ID, i.e. the one ExportAsset was first
+	// submitted with on hops[0] — not whatever locally-wrapped asset ID an
+	// intermediate hop's import produced. Carrying it forward unchanged at
+	// every leg is what lets ImportedAssetID on the final hop resolve to the
+	// true origin instead of an imported-of-imported chain.
+	Asset  ids.ID
+	Value  uint64
+	Return bool
+}
+
+type pendingExport struct {
+	resultCh chan importResult
+	params   HopParams
+	// originTxID is the exportTxID the caller originally passed to
+	// Relay/RelayHop, i.e. the key this pendingExport is stored under in
+	// route.byOrigin. It stays fixed as hopIdx advances and the leg
+	// currently tracked in route.byCurrent changes key.
+	originTxID ids.ID
+	// hopIdx is the index into route.hops of the leg currently in flight:
+	// hops[hopIdx] -> hops[hopIdx+1].
+	hopIdx int
+	// registeredAt is when Relay/RelayHop first registered this export, used
+	// to report end-to-end relay latency once it's delivered.
+	registeredAt time.Time
+	// bo tracks retry backoff for the current leg after a failed
+	// aggregation or submission attempt.
+	bo backoff
+}
+
+type importResult struct {
+	txID ids.ID
+	fee  uint64
+	err  error
+}
+
+// Relayer services a set of Routes, each relaying ExportAsset transactions
+// from one subnet to an ImportAsset transaction on another.
+type Relayer struct {
+	log        logging.Logger
+	routes     map[ids.ID]*route // keyed by SourceBlockchainID
+	metrics    *Metrics
+	checkpoint Checkpoint
+}
+
+// Clients bundles the RPC clients a Relayer needs per blockchain: an
+// hrpc.JSONRPCClient (for GenerateAggregateWarpSignature/GenerateTransaction)
+// and an nrpc.JSONRPCClient (for Parser/WaitForTransaction), both keyed by
+// blockchain ID.
+type Clients struct {
+	HCLI map[ids.ID]*hrpc.JSONRPCClient
+	NCLI map[ids.ID]*nrpc.JSONRPCClient
+}
+
+// New builds a Relayer for every route in cfg. clients must contain an hcli
+// and ncli entry for every chain named in each route's Hops (for a direct
+// route, that's just its source and destination).
+func New(log logging.Logger, cfg *Config, clients Clients) (*Relayer, error) {
+	routes := make(map[ids.ID]*route, len(cfg.Routes))
+	for _, rc := range cfg.Routes {
+		hops := rc.Hops
+		if len(hops) == 0 {
+			hops = []ids.ID{rc.SourceBlockchainID, rc.DestinationBlockchainID}
+		}
+
+		// hops[0] only ever originates a leg (GenerateAggregateWarpSignature,
+		// via its hcli); every later hop also lands one (submitImport, or a
+		// follow-on submitExport for an intermediate hop), so only those need
+		// an ncli too.
+		resolved := make([]hopClients, len(hops))
+		for i, chainID := range hops {
+			hcli, ok := clients.HCLI[chainID]
+			if !ok {
+				return nil, fmt.Errorf("no hrpc client configured for hop %s", chainID)
+			}
+			var ncli *nrpc.JSONRPCClient
+			if i > 0 {
+				ncli, ok = clients.NCLI[chainID]
+				if !ok {
+					return nil, fmt.Errorf("no nrpc client configured for hop %s", chainID)
+				}
+			}
+			resolved[i] = hopClients{blockchainID: chainID, hcli: hcli, ncli: ncli}
+		}
+
+		privBytes, err := codec.LoadHex(rc.SigningKey, ed25519.PrivateKeyLen)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signing key for route %s->%s: %w", rc.SourceBlockchainID, rc.DestinationBlockchainID, err)
+		}
+
+		if _, ok := routes[rc.SourceBlockchainID]; ok {
+			return nil, fmt.Errorf("duplicate route for source blockchain %s", rc.SourceBlockchainID)
+		}
+		routes[rc.SourceBlockchainID] = &route{
+			cfg:       rc,
+			hops:      resolved,
+			factory:   auth.NewED25519Factory(ed25519.PrivateKey(privBytes)),
+			backoff:   cfg.Backoff,
+			byOrigin:  map[ids.ID]*pendingExport{},
+			byCurrent: map[ids.ID]*pendingExport{},
+		}
+	}
+
+	checkpoint := Checkpoint(NewInMemoryCheckpoint())
+	if cfg.CheckpointPath != "" {
+		var err error
+		checkpoint, err = NewFileCheckpoint(cfg.CheckpointPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Relayer{log: log, routes: routes, metrics: NewMetrics(), checkpoint: checkpoint}, nil
+}
+
+// Metrics returns the relayer's Metrics, whose Registry is what to serve on
+// a `/metrics` endpoint.
+func (r *Relayer) Metrics() *Metrics {
+	return r.metrics
+}
+
+// SetCheckpoint swaps in a Checkpoint (e.g. a FileCheckpoint loaded from a
+// different path, or a shared store) in place of the one New configured from
+// Config.CheckpointPath. Must be called before Relay/RelayHop/Run.
+func (r *Relayer) SetCheckpoint(cp Checkpoint) {
+	r.checkpoint = cp
+}
+
+// Relay registers [exportTxID], submitted against [source], to be relayed to
+// its route's destination. It returns an error if no route is configured for
+// [source]. It is equivalent to RelayHop with a zero HopParams, which is
+// only ever read by a multi-hop route's auto-chaining logic.
+//
+// Ideally the relayer would discover exports itself by indexing accepted
+// blocks on each source subnet; today callers (the vm's tx-accepted hook, or
+// the e2e suite) report export txIDs directly as they submit them, which
+// gives the same aggregate-and-forward behavior without requiring a block
+// subscription API.
+func (r *Relayer) Relay(source ids.ID, exportTxID ids.ID) error {
+	return r.RelayHop(source, exportTxID, HopParams{})
+}
+
+// RelayHop is Relay extended with the transfer parameters a multi-hop route
+// needs to reconstruct the ExportAsset for every leg past the first. Direct
+// routes may ignore params and call Relay instead.
+func (r *Relayer) RelayHop(source ids.ID, exportTxID ids.ID, params HopParams) error {
+	rt, ok := r.routes[source]
+	if !ok {
+		return fmt.Errorf("no relay route configured for source blockchain %s", source)
+	}
+
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if _, ok := rt.byOrigin[exportTxID]; ok {
+		return nil
+	}
+
+	pe := &pendingExport{resultCh: make(chan importResult, 1), params: params, originTxID: exportTxID, hopIdx: 0, registeredAt: time.Now()}
+	rt.byOrigin[exportTxID] = pe
+
+	// Resume from the furthest hop this export already landed before a
+	// crash/restart, so Run never re-submits an ImportAsset for a leg that
+	// already completed. currentLegTxID starts as exportTxID, which is the
+	// leg hops[0]->hops[1]'s own export txID, matching what byCurrent would
+	// hold with no checkpointed progress at all.
+	currentLegTxID := exportTxID
+	for {
+		rec, ok := r.checkpoint.Imported(source, exportTxID, pe.hopIdx)
+		if !ok {
+			break
+		}
+		if rec.Final {
+			pe.resultCh <- importResult{txID: rec.ImportTxID, fee: rec.Fee}
+			return nil
+		}
+		if rec.NextLegTxID == ids.Empty {
+			// The import for this hop landed, but the follow-on export
+			// toward the next hop was never durably recorded as submitted —
+			// the process likely crashed between the two. Resubmit just the
+			// export; the ImportAsset that already landed is not retried.
+			dest := rt.hops[pe.hopIdx+1]
+			nextHop := rt.hops[pe.hopIdx+2].blockchainID
+			nextLegTxID, err := submitExport(context.Background(), dest, nextHop, rt.factory, params)
+			if err != nil {
+				return fmt.Errorf("failed to resume relaying %s past hop %d: %w", exportTxID, pe.hopIdx, err)
+			}
+			rec.NextLegTxID = nextLegTxID
+			if cerr := r.checkpoint.MarkImported(source, exportTxID, pe.hopIdx, rec); cerr != nil && r.log != nil {
+				r.log.Error("failed to persist relay checkpoint", zap.Stringer("exportTxID", exportTxID), zap.Error(cerr))
+			}
+		}
+		currentLegTxID = rec.NextLegTxID
+		pe.hopIdx++
+	}
+
+	rt.byCurrent[currentLegTxID] = pe
+	return nil
+}
+
+// WaitForImport blocks until the ImportAsset transaction relaying
+// [exportTxID] (previously passed to Relay/RelayHop) has landed on the
+// route's final destination — every intermediate hop's auto-chained
+// export/import has already completed by the time this returns — or ctx is
+// done.
+func (r *Relayer) WaitForImport(ctx context.Context, source ids.ID, exportTxID ids.ID) (ids.ID, uint64, error) {
+	rt, ok := r.routes[source]
+	if !ok {
+		return ids.Empty, 0, fmt.Errorf("no relay route configured for source blockchain %s", source)
+	}
+
+	rt.mu.Lock()
+	pe, ok := rt.byOrigin[exportTxID]
+	rt.mu.Unlock()
+	if !ok {
+		return ids.Empty, 0, fmt.Errorf("export %s was never registered with Relay", exportTxID)
+	}
+
+	select {
+	case res := <-pe.resultCh:
+		return res.txID, res.fee, res.err
+	case <-ctx.Done():
+		return ids.Empty, 0, ctx.Err()
+	}
+}
+
+// Run services every configured route until ctx is done, aggregating
+// signatures for pending exports and submitting the corresponding
+// ImportAsset transactions as signature weight crosses each route's
+// MinStakeWeightPercent threshold.
+func (r *Relayer) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, rt := range r.routes {
+		wg.Add(1)
+		go func(rt *route) {
+			defer wg.Done()
+			r.runRoute(ctx, rt)
+		}(rt)
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+func (r *Relayer) runRoute(ctx context.Context, rt *route) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.pollRoute(ctx, rt)
+		}
+	}
+}
+
+func (r *Relayer) pollRoute(ctx context.Context, rt *route) {
+	rt.mu.Lock()
+	legTxIDs := make([]ids.ID, 0, len(rt.byCurrent))
+	for txID := range rt.byCurrent {
+		legTxIDs = append(legTxIDs, txID)
+	}
+	rt.mu.Unlock()
+
+	r.metrics.setPending(rt.cfg.SourceBlockchainID, len(legTxIDs))
+
+	now := time.Now()
+	var pollErr error
+	for _, legTxID := range legTxIDs {
+		rt.mu.Lock()
+		pe, ok := rt.byCurrent[legTxID]
+		rt.mu.Unlock()
+		if !ok || !pe.bo.ready(now) {
+			continue
+		}
+
+		sourceHCLI := rt.hops[pe.hopIdx].hcli
+		msg, subnetWeight, sigWeight, err := sourceHCLI.GenerateAggregateWarpSignature(ctx, legTxID)
+		if err != nil {
+			pe.bo.fail(rt.backoff, now)
+			pollErr = err
+			continue
+		}
+		if subnetWeight == 0 || float64(sigWeight)*100/float64(subnetWeight) < rt.cfg.MinStakeWeightPercent {
+			continue
+		}
+
+		r.completeLeg(ctx, rt, legTxID, pe, msg)
+	}
+
+	rt.mu.Lock()
+	rt.lastPollAt = now
+	rt.lastPollErr = pollErr
+	rt.mu.Unlock()
+}
+
+// completeLeg submits the ImportAsset for the leg that just reached
+// signature quorum. If that leg lands on an intermediate hop rather than the
+// route's final destination, it auto-submits the ExportAsset for the next
+// leg and keeps pe pending under the new leg's txID; otherwise it delivers
+// the final result to pe.resultCh.
+//
+// A failure to submit the ImportAsset itself retries (with backoff) on the
+// same leg rather than giving up immediately, up to rt.backoff.MaxAttempts —
+// GenerateAggregateWarpSignature is safe to recompute and ImportAsset hasn't
+// landed yet, so there's nothing to double-submit. Once the import has
+// landed, it's checkpointed immediately, before the follow-on export is ever
+// attempted, so a crash between the two doesn't re-land it: RelayHop resumes
+// by re-submitting only the follow-on export. A failure to submit that
+// follow-on export is not retried here, since retrying completeLeg from the
+// top would resubmit (and double-land) the import that already succeeded;
+// that failure is instead delivered to pe.resultCh as a terminal error, same
+// as the pre-retry behavior.
+func (r *Relayer) completeLeg(ctx context.Context, rt *route, legTxID ids.ID, pe *pendingExport, msg *warp.Message) {
+	dest := rt.hops[pe.hopIdx+1]
+	importTxID, fee, importErr := submitImport(ctx, dest, rt.factory, msg)
+	if importErr != nil {
+		giveUp := rt.backoff.withDefaults().MaxAttempts > 0 && pe.bo.attempt >= rt.backoff.withDefaults().MaxAttempts
+		if !giveUp {
+			pe.bo.fail(rt.backoff, time.Now())
+			if r.log != nil {
+				r.log.Warn("failed to relay export, will retry", zap.Stringer("exportTxID", legTxID), zap.Int("attempt", pe.bo.attempt), zap.Error(importErr))
+			}
+			return // pe stays registered under legTxID; the next poll retries it.
+		}
+
+		rt.mu.Lock()
+		delete(rt.byOrigin, pe.originTxID)
+		delete(rt.byCurrent, legTxID)
+		rt.mu.Unlock()
+
+		if r.log != nil {
+			r.log.Error("giving up relaying export", zap.Stringer("exportTxID", legTxID), zap.Int("attempts", pe.bo.attempt), zap.Error(importErr))
+		}
+		r.metrics.recordFailure(rt.cfg.SourceBlockchainID)
+		pe.resultCh <- importResult{txID: importTxID, fee: fee, err: importErr}
+		return
+	}
+
+	structurallyFinal := pe.hopIdx+1 == len(rt.hops)-1
+	rec := LegRecord{ImportTxID: importTxID, Fee: fee, Final: structurallyFinal}
+	if cerr := r.checkpoint.MarkImported(rt.cfg.SourceBlockchainID, pe.originTxID, pe.hopIdx, rec); cerr != nil && r.log != nil {
+		r.log.Error("failed to persist relay checkpoint", zap.Stringer("exportTxID", legTxID), zap.Error(cerr))
+	}
+
+	final := structurallyFinal
+	var nextLegTxID, nextHop ids.ID
+	var err error
+	if !final {
+		nextHop = rt.hops[pe.hopIdx+2].blockchainID
+		nextLegTxID, err = submitExport(ctx, dest, nextHop, rt.factory, pe.params)
+		final = err != nil
+		if err == nil {
+			rec.NextLegTxID = nextLegTxID
+			if cerr := r.checkpoint.MarkImported(rt.cfg.SourceBlockchainID, pe.originTxID, pe.hopIdx, rec); cerr != nil && r.log != nil {
+				r.log.Error("failed to persist relay checkpoint", zap.Stringer("exportTxID", legTxID), zap.Error(cerr))
+			}
+		}
+	}
+
+	rt.mu.Lock()
+	if final {
+		delete(rt.byOrigin, pe.originTxID)
+		delete(rt.byCurrent, legTxID)
+	} else {
+		delete(rt.byCurrent, legTxID)
+		pe.hopIdx++
+		pe.bo.reset()
+		rt.byCurrent[nextLegTxID] = pe
+	}
+	rt.mu.Unlock()
+
+	if r.log != nil {
+		switch {
+		case err != nil:
+			r.log.Warn("failed to relay follow-on export", zap.Stringer("exportTxID", legTxID), zap.Error(err))
+		case final:
+			r.log.Info("relayed export", zap.Stringer("exportTxID", legTxID), zap.Stringer("importTxID", importTxID))
+		default:
+			r.log.Info("relaying multi-hop export to next hop",
+				zap.Stringer("exportTxID", legTxID),
+				zap.Stringer("importTxID", importTxID),
+				zap.Stringer("nextExportTxID", nextLegTxID),
+				zap.Stringer("nextHop", nextHop),
+			)
+		}
+	}
+
+	if !final {
+		return
+	}
+	if err != nil {
+		r.metrics.recordFailure(rt.cfg.SourceBlockchainID)
+		pe.resultCh <- importResult{txID: importTxID, fee: fee, err: err}
+		return
+	}
+	r.metrics.recordRelayed(rt.cfg.SourceBlockchainID)
+	r.metrics.observeRelayLatency(time.Since(pe.registeredAt).Seconds())
+	pe.resultCh <- importResult{txID: importTxID, fee: fee}
+}
+
+func submitImport(ctx context.Context, dest hopClients, factory *auth.ED25519Factory, msg *warp.Message) (ids.ID, uint64, error) {
+	parser, err := dest.ncli.Parser(ctx)
+	if err != nil {
+		return ids.Empty, 0, err
+	}
+	submit, tx, _, err := dest.hcli.GenerateTransaction(ctx, parser, msg, &actions.ImportAsset{}, factory)
+	if err != nil {
+		return ids.Empty, 0, err
+	}
+	if err := submit(ctx); err != nil {
+		return ids.Empty, 0, err
+	}
+	success, fee, err := dest.ncli.WaitForTransaction(ctx, tx.ID())
+	if err != nil {
+		return ids.Empty, 0, err
+	}
+	if !success {
+		return ids.Empty, 0, fmt.Errorf("import transaction %s reverted", tx.ID())
+	}
+	return tx.ID(), fee, nil
+}
+
+// submitExport submits the ExportAsset that carries a multi-hop transfer
+// from the chain it just landed on (source) toward nextHop. Asset is the
+// true origin asset ID, not whatever locally-wrapped asset ID source's
+// import produced, and Route records only this leg, [source, nextHop]; the
+// hops after nextHop are not listed in it.
+func submitExport(ctx context.Context, source hopClients, nextHop ids.ID, factory *auth.ED25519Factory, params HopParams) (ids.ID, error) {
+	parser, err := source.ncli.Parser(ctx)
+	if err != nil {
+		return ids.Empty, err
+	}
+	submit, tx, _, err := source.hcli.GenerateTransaction(
+		ctx,
+		parser,
+		nil,
+		&actions.ExportAsset{
+			To:          params.To,
+			Asset:       params.Asset,
+			Value:       params.Value,
+			Return:      params.Return,
+			Destination: nextHop,
+			Route:       []ids.ID{source.blockchainID, nextHop},
+		},
+		factory,
+	)
+	if err != nil {
+		return ids.Empty, err
+	}
+	if err := submit(ctx); err != nil {
+		return ids.Empty, err
+	}
+	success, _, err := source.ncli.WaitForTransaction(ctx, tx.ID())
+	if err != nil {
+		return ids.Empty, err
+	}
+	if !success {
+		return ids.Empty, fmt.Errorf("export transaction %s reverted", tx.ID())
+	}
+	return tx.ID(), nil
+}
@@ -0,0 +1,195 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	hrpc "github.com/ava-labs/hypersdk/rpc"
+	"go.uber.org/zap"
+
+	"github.com/nuklai/nuklaivm/actions"
+	"github.com/nuklai/nuklaivm/auth"
+	nrpc "github.com/nuklai/nuklaivm/rpc"
+)
+
+// DelegationEvent is one entry in a source chain's delegation log, already
+// attested by that chain's committee. Withdraw is false for a delegation and
+// true for a withdrawal; the StakeBridge maps that onto
+// RemoteDelegateUserStake or RemoteWithdrawUserStake respectively.
+type DelegationEvent struct {
+	SourceChainID   ids.ID
+	NodeID          []byte
+	Delegator       codec.Address
+	StakeStartBlock uint64
+	StakeEndBlock   uint64
+	StakedAmount    uint64
+	RewardAddress   codec.Address
+	Nonce           uint64
+	Signature       []byte
+	Withdraw        bool
+}
+
+// EventSource is the source chain's delegation log, as the StakeBridge needs
+// it. A real implementation polls the source chain's own RPC (or indexer);
+// the e2e suite can instead hand the bridge an in-memory source feeding
+// events it constructs directly, the same way the asset Relayer is driven
+// directly by Relay/RelayHop instead of a block subscription.
+type EventSource interface {
+	// PollSince returns every DelegationEvent recorded after cursor, along
+	// with the cursor PollSince should be called with next.
+	PollSince(ctx context.Context, cursor uint64) ([]DelegationEvent, uint64, error)
+}
+
+// StakeBridgeConfig configures a single StakeBridge.
+type StakeBridgeConfig struct {
+	// SigningKey is the hex-encoded ED25519 private key the bridge uses to
+	// pay fees for RemoteDelegateUserStake/RemoteWithdrawUserStake
+	// transactions on nuklaivm. It is unrelated to the committee key that
+	// attests to the events themselves.
+	SigningKey string `json:"signingKey" yaml:"signingKey"`
+	// PollInterval is how often the bridge checks EventSource for new
+	// events. Defaults to pollInterval.
+	PollInterval time.Duration `json:"pollInterval,omitempty" yaml:"pollInterval,omitempty"`
+}
+
+// StakeBridge watches a source chain's delegation event log (via
+// EventSource) and submits the matching RemoteDelegateUserStake or
+// RemoteWithdrawUserStake transaction on nuklaivm for each event it hasn't
+// already relayed. Unlike Relayer, it never aggregates signatures itself:
+// the committee attestation already lives on each DelegationEvent by the
+// time PollSince returns it.
+type StakeBridge struct {
+	log     logging.Logger
+	source  EventSource
+	hcli    *hrpc.JSONRPCClient
+	ncli    *nrpc.JSONRPCClient
+	factory *auth.ED25519Factory
+	cfg     StakeBridgeConfig
+	metrics *Metrics
+
+	cursor uint64
+}
+
+// NewStakeBridge builds a StakeBridge submitting against hcli/ncli, paying
+// fees with cfg.SigningKey.
+func NewStakeBridge(log logging.Logger, cfg StakeBridgeConfig, source EventSource, hcli *hrpc.JSONRPCClient, ncli *nrpc.JSONRPCClient) (*StakeBridge, error) {
+	privBytes, err := codec.LoadHex(cfg.SigningKey, ed25519.PrivateKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stake bridge signing key: %w", err)
+	}
+	return &StakeBridge{
+		log:     log,
+		source:  source,
+		hcli:    hcli,
+		ncli:    ncli,
+		factory: auth.NewED25519Factory(ed25519.PrivateKey(privBytes)),
+		cfg:     cfg,
+		metrics: NewMetrics(),
+	}, nil
+}
+
+// Metrics returns the bridge's Metrics, whose Registry is what to serve on a
+// `/metrics` endpoint.
+func (b *StakeBridge) Metrics() *Metrics {
+	return b.metrics
+}
+
+// Run polls source for new events and submits the matching transaction for
+// each, until ctx is done. A submission failure is logged and retried on the
+// next poll rather than aborting the loop, since the next PollSince call
+// will return the same un-relayed events again.
+func (b *StakeBridge) Run(ctx context.Context) error {
+	interval := b.cfg.PollInterval
+	if interval <= 0 {
+		interval = pollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			b.poll(ctx)
+		}
+	}
+}
+
+func (b *StakeBridge) poll(ctx context.Context) {
+	events, next, err := b.source.PollSince(ctx, b.cursor)
+	if err != nil {
+		if b.log != nil {
+			b.log.Warn("failed to poll delegation event source", zap.Error(err))
+		}
+		return
+	}
+	for _, ev := range events {
+		if err := b.submit(ctx, ev); err != nil {
+			if b.log != nil {
+				b.log.Error("failed to submit remote stake transaction", zap.Stringer("sourceChainID", ev.SourceChainID), zap.Stringer("delegator", ev.Delegator), zap.Error(err))
+			}
+			b.metrics.recordFailure(ev.SourceChainID)
+			return
+		}
+		b.metrics.recordRelayed(ev.SourceChainID)
+	}
+	b.cursor = next
+}
+
+func (b *StakeBridge) submit(ctx context.Context, ev DelegationEvent) error {
+	parser, err := b.ncli.Parser(ctx)
+	if err != nil {
+		return err
+	}
+
+	submit, tx, _, err := b.hcli.GenerateTransaction(ctx, parser, nil, b.action(ev), b.factory)
+	if err != nil {
+		return err
+	}
+	if err := submit(ctx); err != nil {
+		return err
+	}
+	success, _, err := b.ncli.WaitForTransaction(ctx, tx.ID())
+	if err != nil {
+		return err
+	}
+	if !success {
+		return fmt.Errorf("remote stake transaction %s reverted", tx.ID())
+	}
+	return nil
+}
+
+// action builds the RemoteDelegateUserStake or RemoteWithdrawUserStake
+// transaction for ev, carrying its committee attestation through unchanged.
+func (b *StakeBridge) action(ev DelegationEvent) chain.Action {
+	if ev.Withdraw {
+		return &actions.RemoteWithdrawUserStake{
+			SourceChainID: ev.SourceChainID,
+			NodeID:        ev.NodeID,
+			Delegator:     ev.Delegator,
+			Nonce:         ev.Nonce,
+			Signature:     ev.Signature,
+		}
+	}
+	return &actions.RemoteDelegateUserStake{
+		SourceChainID:   ev.SourceChainID,
+		NodeID:          ev.NodeID,
+		Delegator:       ev.Delegator,
+		StakeStartBlock: ev.StakeStartBlock,
+		StakeEndBlock:   ev.StakeEndBlock,
+		StakedAmount:    ev.StakedAmount,
+		RewardAddress:   ev.RewardAddress,
+		Nonce:           ev.Nonce,
+		Signature:       ev.Signature,
+	}
+}
@@ -65,6 +65,23 @@ func (cli *JSONRPCClient) Genesis(ctx context.Context) (*genesis.Genesis, error)
 	return resp.Genesis, nil
 }
 
+// PendingUpgrades returns the genesis.GenesisUpgrade entries that haven't
+// activated as of the node's last accepted block, so operators can confirm
+// what's scheduled and when before it takes effect.
+func (cli *JSONRPCClient) PendingUpgrades(ctx context.Context) ([]genesis.GenesisUpgrade, error) {
+	resp := new(PendingUpgradesReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"pendingUpgrades",
+		nil,
+		resp,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Upgrades, nil
+}
+
 func (cli *JSONRPCClient) Tx(ctx context.Context, id ids.ID) (bool, bool, int64, uint64, error) {
 	resp := new(TxReply)
 	err := cli.requester.SendRequest(
@@ -281,10 +298,24 @@ type Parser struct {
 	networkID uint32
 	chainID   ids.ID
 	genesis   *genesis.Genesis
+	height    uint64
 }
 
-func (p *Parser) Rules(t int64) chain.Rules {
-	return p.genesis.Rules(t, p.networkID, p.chainID)
+// Rules returns the chain.Rules active at the height cli.Parser observed
+// when this Parser was constructed, with every GenesisUpgrade whose
+// ActivationHeight has passed by then applied via RulesAt. t is unused:
+// upgrades schedule against block height, not timestamp, and chain.Parser's
+// Rules(t int64) signature has no height parameter to thread one through.
+func (p *Parser) Rules(_ int64) chain.Rules {
+	rules, err := p.genesis.RulesAt(p.height, p.networkID, p.chainID)
+	if err != nil {
+		// Every upgrade's overlay was already validated to merge cleanly in
+		// genesis.New, so this can't happen outside of a corrupt snapshot;
+		// fall back to the un-upgraded base rules rather than failing a
+		// method the chain.Parser interface declares infallible.
+		return p.genesis.Rules(0, p.networkID, p.chainID)
+	}
+	return rules
 }
 
 func (*Parser) Registry() (chain.ActionRegistry, chain.AuthRegistry) {
@@ -296,5 +327,9 @@ func (cli *JSONRPCClient) Parser(ctx context.Context) (chain.Parser, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Parser{cli.networkID, cli.chainID, g}, nil
+	height, _, _, _, _, _, _, err := cli.EmissionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Parser{cli.networkID, cli.chainID, g, height}, nil
 }
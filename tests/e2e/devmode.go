@@ -0,0 +1,304 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package e2e_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	hrpc "github.com/ava-labs/hypersdk/rpc"
+	hutils "github.com/ava-labs/hypersdk/utils"
+
+	"github.com/nuklai/nuklaivm/actions"
+	"github.com/nuklai/nuklaivm/auth"
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/genesis"
+	nrpc "github.com/nuklai/nuklaivm/rpc"
+	nvm "github.com/nuklai/nuklaivm/vm"
+)
+
+// devFundedKey is the only key devmode funds in genesis. It matches the
+// default sender key loaded elsewhere in this suite so the same CLI/test
+// helpers work unmodified against a dev node.
+const devFundedKey = "323b1d8f4eed5f0da9da93071b034f2dce9d2d22692c172f3cb252a64ddfafd01b057de320297c29ad0c1f589ea216869cf1938d88c9fbd70d6748323dbf2fa7" //nolint:lll
+
+// devAdminExtension is the path suffix the admin handler is mounted under,
+// alongside whatever extensions the vm's own CreateHandlers registers.
+const devAdminExtension = "/admin"
+
+// noopAppSender satisfies common.AppSender by embedding it unset: a devNode
+// is the only participant on its subnet, so the vm never has a peer to
+// gossip a message to and these methods are never actually invoked.
+type noopAppSender struct {
+	common.AppSender
+}
+
+// devNode is a single in-process nuklaivm instance with no avalanche-network-runner,
+// no other validators, and an in-memory database. Its genesis zeroes
+// MinBlockGap/MinEmptyBlockGap, so the instant-seal loop below builds and
+// accepts a block as soon as a transaction lands in the mempool. It exists
+// to give contributors a local endpoint for iterating on actions/auth
+// without a multi-minute ANR cluster bring-up; it runs a single chain with
+// no peers, so warp-dependent flows (ExportAsset/ImportAsset) are out of
+// scope here.
+type devNode struct {
+	vm       *nvm.VM
+	chainID  ids.ID
+	toEngine chan common.Message
+
+	listener net.Listener
+	server   *http.Server
+
+	uri  string
+	hcli *hrpc.JSONRPCClient
+	ncli *nrpc.JSONRPCClient
+
+	priv    ed25519.PrivateKey
+	factory *auth.ED25519Factory
+	sender  string
+
+	mu sync.Mutex
+}
+
+// startDevNode builds and starts a devNode listening on an OS-assigned local
+// port. Callers must call Shutdown when done.
+func startDevNode() (*devNode, error) {
+	privBytes, err := codec.LoadHex(devFundedKey, ed25519.PrivateKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dev funded key: %w", err)
+	}
+	priv := ed25519.PrivateKey(privBytes)
+	rsender := auth.NewED25519Address(priv.PublicKey())
+	sender := codec.MustAddressBech32(nconsts.HRP, rsender)
+
+	g := genesis.Default()
+	g.MinBlockGap = 0
+	g.MinEmptyBlockGap = 0
+	g.CustomAllocation = []*genesis.CustomAllocation{
+		{Address: sender, Balance: startAmount},
+	}
+	genesisBytes, err := json.Marshal(g)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal dev genesis: %w", err)
+	}
+
+	chainID := ids.GenerateTestID()
+	toEngine := make(chan common.Message, 1)
+
+	vm := nvm.New()
+	if err := vm.Initialize(
+		context.Background(),
+		&snow.Context{
+			NetworkID: 0,
+			SubnetID:  ids.Empty,
+			ChainID:   chainID,
+			NodeID:    ids.EmptyNodeID,
+			Log:       logging.NoLog{},
+		},
+		memdb.New(),
+		genesisBytes,
+		nil,
+		[]byte("{}"),
+		toEngine,
+		nil,
+		&noopAppSender{},
+	); err != nil {
+		return nil, fmt.Errorf("failed to initialize dev vm: %w", err)
+	}
+	if err := vm.SetState(context.Background(), snow.NormalOp); err != nil {
+		return nil, fmt.Errorf("failed to mark dev vm healthy: %w", err)
+	}
+
+	handlers, err := vm.CreateHandlers(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dev vm handlers: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dev listener: %w", err)
+	}
+	uri := fmt.Sprintf("http://%s/ext/bc/%s", listener.Addr().String(), chainID)
+
+	d := &devNode{
+		vm:       vm,
+		chainID:  chainID,
+		toEngine: toEngine,
+		listener: listener,
+		uri:      uri,
+		priv:     priv,
+		factory:  auth.NewED25519Factory(priv),
+		sender:   sender,
+	}
+
+	mux := http.NewServeMux()
+	base := fmt.Sprintf("/ext/bc/%s", chainID)
+	for extension, handler := range handlers {
+		mux.Handle(base+extension, handler)
+	}
+	mux.HandleFunc(base+devAdminExtension, d.handleAdmin)
+	d.server = &http.Server{Handler: mux} //nolint:gosec
+
+	go d.server.Serve(listener) //nolint:errcheck
+
+	// Instant-seal: whenever the mempool notifies the (nonexistent) engine
+	// that it has a pending tx to build around, build and accept a block
+	// immediately instead of waiting on a consensus round.
+	go func() {
+		for range toEngine {
+			_, _ = d.mineBlock(context.Background())
+		}
+	}()
+
+	d.hcli = hrpc.NewJSONRPCClient(uri)
+	var networkID uint32
+	for i := 0; i < 10; i++ {
+		networkID, _, _, err = d.hcli.Network(context.Background())
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dev vm never came up: %w", err)
+	}
+	d.ncli = nrpc.NewJSONRPCClient(uri, networkID, chainID)
+
+	hutils.Outf("{{green}}dev node listening:{{/}} %s\n", uri)
+	return d, nil
+}
+
+// mineBlock builds, verifies, and accepts a single block, returning its ID.
+// It does not check the mempool first: it always calls the vm's BuildBlock
+// and returns ids.Empty with BuildBlock's error if that fails.
+func (d *devNode) mineBlock(ctx context.Context) (ids.ID, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	blk, err := d.vm.BuildBlock(ctx)
+	if err != nil {
+		return ids.Empty, err
+	}
+	if err := blk.Verify(ctx); err != nil {
+		return ids.Empty, err
+	}
+	if err := d.vm.SetPreference(ctx, blk.ID()); err != nil {
+		return ids.Empty, err
+	}
+	if err := blk.Accept(ctx); err != nil {
+		return ids.Empty, err
+	}
+	return blk.ID(), nil
+}
+
+// devAdminRequest backs the handful of JSON-RPC-ish methods the admin
+// handler exposes (dev_nodeInfo, dev_mineBlock, dev_fundAddress). Unlike the
+// vm's own JSON-RPC surface, this is devmode-only and not meant to be
+// depended on outside this suite.
+type devAdminRequest struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type devFundAddressParams struct {
+	Address string `json:"address"`
+	Amount  uint64 `json:"amount"`
+}
+
+func (d *devNode) handleAdmin(w http.ResponseWriter, r *http.Request) {
+	var req devAdminRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch req.Method {
+	case "dev_nodeInfo":
+		d.writeJSON(w, map[string]any{
+			"chainID":    d.chainID.String(),
+			"uri":        d.uri,
+			"fundedKeys": []string{d.sender},
+		})
+
+	case "dev_mineBlock":
+		blkID, err := d.mineBlock(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		d.writeJSON(w, map[string]any{"blockID": blkID.String()})
+
+	case "dev_fundAddress":
+		var params devFundAddressParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		txID, err := d.fundAddress(r.Context(), params.Address, params.Amount)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		d.writeJSON(w, map[string]any{"txID": txID.String()})
+
+	default:
+		http.Error(w, fmt.Sprintf("unknown dev admin method %q", req.Method), http.StatusBadRequest)
+	}
+}
+
+func (d *devNode) writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// fundAddress transfers [amount] from the dev-funded key to [address],
+// mining the resulting block immediately.
+func (d *devNode) fundAddress(ctx context.Context, address string, amount uint64) (ids.ID, error) {
+	to, err := codec.ParseAddressBech32(nconsts.HRP, address)
+	if err != nil {
+		return ids.Empty, err
+	}
+
+	parser, err := d.ncli.Parser(ctx)
+	if err != nil {
+		return ids.Empty, err
+	}
+	submit, tx, _, err := d.hcli.GenerateTransaction(
+		ctx,
+		parser,
+		nil,
+		&actions.Transfer{To: to, Value: amount},
+		d.factory,
+	)
+	if err != nil {
+		return ids.Empty, err
+	}
+	if err := submit(ctx); err != nil {
+		return ids.Empty, err
+	}
+	if _, err := d.mineBlock(ctx); err != nil {
+		return ids.Empty, err
+	}
+	return tx.ID(), nil
+}
+
+// Shutdown tears down the dev node's HTTP server and vm.
+func (d *devNode) Shutdown(ctx context.Context) error {
+	close(d.toEngine)
+	_ = d.server.Close()
+	return d.vm.Shutdown(ctx)
+}
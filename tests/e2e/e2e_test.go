@@ -7,10 +7,8 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
-	"strings"
 	"testing"
 	"time"
 
@@ -33,9 +31,18 @@ import (
 	"github.com/nuklai/nuklaivm/actions"
 	"github.com/nuklai/nuklaivm/auth"
 	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/loadgen"
+	"github.com/nuklai/nuklaivm/relayer"
 	nrpc "github.com/nuklai/nuklaivm/rpc"
+	"github.com/nuklai/nuklaivm/tests/vectors"
 )
 
+// relayerSigningKey is the hex-encoded ED25519 key the embedded relayer signs
+// ImportAsset transactions with in these tests. It intentionally matches the
+// default sender key loaded below: the e2e suite only needs *a* funded key to
+// pay import fees, not a dedicated relayer identity.
+const relayerSigningKey = "323b1d8f4eed5f0da9da93071b034f2dce9d2d22692c172f3cb252a64ddfafd01b057de320297c29ad0c1f589ea216869cf1938d88c9fbd70d6748323dbf2fa7" //nolint:lll
+
 const (
 	startAmount = uint64(10000000000000000000)
 	sendAmount  = uint64(5000)
@@ -71,10 +78,15 @@ var (
 
 	blockchainIDA string
 	blockchainIDB string
+	blockchainIDC string
 
 	trackSubnetsOpt runner_sdk.OpOption
 
 	numValidators uint
+
+	plaintextNodeInfo bool
+
+	distSigningKeyPath string
 )
 
 func init() {
@@ -158,7 +170,8 @@ func init() {
 		&mode,
 		"mode",
 		"test",
-		"'test' to shut down cluster after tests, 'run' to skip tests and only run without shutdown",
+		"'test' to shut down cluster after tests, 'run' to skip tests and only run without shutdown, "+
+			"'dev' to skip ANR entirely and run tests against a single in-process node",
 	)
 
 	flag.UintVar(
@@ -167,6 +180,26 @@ func init() {
 		5,
 		"number of validators per blockchain",
 	)
+
+	flag.BoolVar(
+		&plaintextNodeInfo,
+		"plaintext-nodeinfo",
+		false,
+		fmt.Sprintf(
+			"copy signer.key/staking.* as plaintext instead of an encrypted bundle; "+
+				"opt out of this for existing CI flows that don't set %s",
+			nodeInfoPassphraseEnv,
+		),
+	)
+
+	flag.StringVar(
+		&distSigningKeyPath,
+		"dist-signing-key",
+		"",
+		"path to an ed25519 distribution key; if set, copyNodeInfo emits a signed "+
+			"node-bootstrap-<nodeID>-<timestamp>.tar.gz instead of a bundle, generating the key at "+
+			"this path on first use",
+	)
 }
 
 const (
@@ -174,17 +207,48 @@ const (
 	modeFullTest  = "full-test" // runs state sync
 	modeRun       = "run"
 	modeRunSingle = "run-single"
+	modeDev       = "dev" // single in-process node, no ANR
 )
 
 var anrCli runner_sdk.Client
 
+// devInstance is the in-process node started instead of an ANR cluster when
+// mode == modeDev.
+var devInstance *devNode
+
 var _ = ginkgo.BeforeSuite(func() {
 	gomega.Expect(mode).Should(gomega.Or(
 		gomega.Equal(modeTest),
 		gomega.Equal(modeFullTest),
 		gomega.Equal(modeRun),
 		gomega.Equal(modeRunSingle),
+		gomega.Equal(modeDev),
 	))
+
+	if mode == modeDev {
+		node, err := startDevNode()
+		gomega.Expect(err).Should(gomega.BeNil())
+		devInstance = node
+
+		blockchainIDA = node.chainID.String()
+		instancesA = []instance{
+			{
+				nodeID:       ids.EmptyNodeID,
+				uri:          node.uri,
+				blockchainID: node.chainID,
+				hcli:         node.hcli,
+				ncli:         node.ncli,
+			},
+		}
+
+		priv = node.priv
+		factory = node.factory
+		rsender = auth.NewED25519Address(priv.PublicKey())
+		sender = node.sender
+		hutils.Outf("\n{{yellow}}$ loaded address:{{/}} %s\n\n", sender)
+		return
+	}
+
 	gomega.Expect(numValidators).Should(gomega.BeNumerically(">", 0))
 	logLevel, err := logging.ToLevel(networkRunnerLogLevel)
 	gomega.Expect(err).Should(gomega.BeNil())
@@ -260,15 +324,19 @@ var _ = ginkgo.BeforeSuite(func() {
 	)
 	logsDir = resp.GetClusterInfo().GetRootDataDir()
 
-	// Name 10 new validators (which should have BLS key registered)
+	// Name 15 new validators (which should have BLS key registered)
 	subnetA := []string{}
 	subnetB := []string{}
-	for i := 1; i <= int(numValidators)*2; i++ {
+	subnetC := []string{}
+	for i := 1; i <= int(numValidators)*3; i++ {
 		n := fmt.Sprintf("node%d-bls", i)
-		if i <= int(numValidators) {
+		switch {
+		case i <= int(numValidators):
 			subnetA = append(subnetA, n)
-		} else {
+		case i <= int(numValidators)*2:
 			subnetB = append(subnetB, n)
+		default:
+			subnetC = append(subnetC, n)
 		}
 	}
 	specs := []*rpcpb.BlockchainSpec{
@@ -290,12 +358,21 @@ var _ = ginkgo.BeforeSuite(func() {
 				Participants: subnetB,
 			},
 		},
+		{
+			VmName:      nconsts.Name,
+			Genesis:     vmGenesisPath,
+			ChainConfig: vmConfigPath,
+			SubnetSpec: &rpcpb.SubnetSpec{
+				SubnetConfig: subnetConfigPath,
+				Participants: subnetC,
+			},
+		},
 	}
 	if mode == modeRunSingle {
 		specs = specs[0:1]
 	}
 
-	// Create 2 subnets
+	// Create subnets (1 in run-single mode, 3 otherwise, so multi-hop warp routing has A, B and C)
 	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Minute)
 	sresp, err := anrCli.CreateBlockchains(
 		ctx,
@@ -327,16 +404,28 @@ var _ = ginkgo.BeforeSuite(func() {
 			subnetIDB,
 			subnetB,
 		)
-		trackSubnetsOpt = runner_sdk.WithGlobalNodeConfig(fmt.Sprintf(`{"%s":"%s,%s"}`,
+
+		blockchainIDC = sresp.ChainIds[2]
+		subnetIDC := sresp.ClusterInfo.CustomChains[blockchainIDC].SubnetId
+		hutils.Outf(
+			"{{green}}successfully added chain:{{/}} %s {{green}}subnet:{{/}} %s {{green}}participants:{{/}} %+v\n",
+			blockchainIDC,
+			subnetIDC,
+			subnetC,
+		)
+
+		trackSubnetsOpt = runner_sdk.WithGlobalNodeConfig(fmt.Sprintf(`{"%s":"%s,%s,%s"}`,
 			config.TrackSubnetsKey,
 			subnetIDA,
 			subnetIDB,
+			subnetIDC,
 		))
 	}
 
 	gomega.Expect(blockchainIDA).Should(gomega.Not(gomega.BeEmpty()))
 	if mode != modeRunSingle {
 		gomega.Expect(blockchainIDB).Should(gomega.Not(gomega.BeEmpty()))
+		gomega.Expect(blockchainIDC).Should(gomega.Not(gomega.BeEmpty()))
 	}
 	gomega.Expect(logsDir).Should(gomega.Not(gomega.BeEmpty()))
 
@@ -370,15 +459,16 @@ var _ = ginkgo.BeforeSuite(func() {
 		gomega.Expect(err).Should(gomega.BeNil())
 
 		instancesA = append(instancesA, instance{
-			nodeID: nodeID,
-			uri:    u,
-			hcli:   hcli,
-			ncli:   nrpc.NewJSONRPCClient(u, networkID, bid),
+			nodeID:       nodeID,
+			uri:          u,
+			blockchainID: bid,
+			hcli:         hcli,
+			ncli:         nrpc.NewJSONRPCClient(u, networkID, bid),
 		})
 
 		// Let's copy the node's staking.cert and staking.key to a temporary directory
 		destDir := fmt.Sprintf("/tmp/nuklaivm/nodes/%s/", info.GetName())
-		err = copyNodeInfo(info.GetDbDir(), destDir)
+		err = copyNodeInfo(info.GetDbDir(), destDir, nodeID, bid, networkID)
 		gomega.Expect(err).Should(gomega.BeNil())
 	}
 
@@ -407,15 +497,53 @@ var _ = ginkgo.BeforeSuite(func() {
 			gomega.Expect(err).Should(gomega.BeNil())
 
 			instancesB = append(instancesB, instance{
-				nodeID: nodeID,
-				uri:    u,
-				hcli:   hcli,
-				ncli:   nrpc.NewJSONRPCClient(u, networkID, bid),
+				nodeID:       nodeID,
+				uri:          u,
+				blockchainID: bid,
+				hcli:         hcli,
+				ncli:         nrpc.NewJSONRPCClient(u, networkID, bid),
 			})
 
 			// Let's copy the node's staking.cert and staking.key to a temporary directory
 			destDir := fmt.Sprintf("/tmp/nuklaivm/nodes/%s/", info.GetName())
-			err = copyNodeInfo(info.GetDbDir(), destDir)
+			err = copyNodeInfo(info.GetDbDir(), destDir, nodeID, bid, networkID)
+			gomega.Expect(err).Should(gomega.BeNil())
+		}
+
+		instancesC = []instance{}
+		for _, nodeName := range subnetC {
+			info := nodeInfos[nodeName]
+			u := fmt.Sprintf("%s/ext/bc/%s", info.Uri, blockchainIDC)
+			bid, err := ids.FromString(blockchainIDC)
+			gomega.Expect(err).Should(gomega.BeNil())
+			nodeID, err := ids.NodeIDFromString(info.GetId())
+			gomega.Expect(err).Should(gomega.BeNil())
+			hcli := hrpc.NewJSONRPCClient(u)
+
+			// After returning healthy, the node may not respond right away
+			//
+			// TODO: figure out why
+			var networkID uint32
+			for i := 0; i < 10; i++ {
+				networkID, _, _, err = hcli.Network(context.TODO())
+				if err != nil {
+					time.Sleep(1 * time.Second)
+					continue
+				}
+			}
+			gomega.Expect(err).Should(gomega.BeNil())
+
+			instancesC = append(instancesC, instance{
+				nodeID:       nodeID,
+				uri:          u,
+				blockchainID: bid,
+				hcli:         hcli,
+				ncli:         nrpc.NewJSONRPCClient(u, networkID, bid),
+			})
+
+			// Let's copy the node's staking.cert and staking.key to a temporary directory
+			destDir := fmt.Sprintf("/tmp/nuklaivm/nodes/%s/", info.GetName())
+			err = copyNodeInfo(info.GetDbDir(), destDir, nodeID, bid, networkID)
 			gomega.Expect(err).Should(gomega.BeNil())
 		}
 	}
@@ -441,13 +569,105 @@ var (
 
 	instancesA []instance
 	instancesB []instance
+	// instancesC backs blockchainIDC, the third subnet stood up alongside A
+	// and B so the [Warp Multi-Hop] suite can relay A -> B -> C.
+	instancesC []instance
 )
 
 type instance struct {
-	nodeID ids.NodeID
-	uri    string
-	hcli   *hrpc.JSONRPCClient
-	ncli   *nrpc.JSONRPCClient
+	nodeID       ids.NodeID
+	uri          string
+	blockchainID ids.ID
+	hcli         *hrpc.JSONRPCClient
+	ncli         *nrpc.JSONRPCClient
+}
+
+// waitForWarpImport relays the ExportAsset transaction [exportTxID], submitted
+// on [source], to [destination] and blocks until the resulting ImportAsset
+// transaction is confirmed there or requestTimeout elapses. It replaces
+// hand-rolled GenerateAggregateWarpSignature polling loops with the same
+// aggregate-and-forward logic the relayer package runs in production.
+//
+// minWeightPercent is the route's MinStakeWeightPercent: the minimum
+// percentage of source-subnet stake weight that must sign before the import
+// is submitted. Passing less than 100 lets a test confirm the relayer
+// imports once quorum is met even while a validator is offline and will
+// never sign, rather than waiting on full weight forever.
+func waitForWarpImport(source, destination instance, exportTxID ids.ID, minWeightPercent float64) (ids.ID, uint64) {
+	rl, err := relayer.New(
+		logging.NoLog{},
+		&relayer.Config{
+			Routes: []relayer.RouteConfig{
+				{
+					SourceBlockchainID:      source.blockchainID,
+					DestinationBlockchainID: destination.blockchainID,
+					SigningKey:              relayerSigningKey,
+					MinStakeWeightPercent:   minWeightPercent,
+				},
+			},
+		},
+		relayer.Clients{
+			HCLI: map[ids.ID]*hrpc.JSONRPCClient{source.blockchainID: source.hcli, destination.blockchainID: destination.hcli},
+			NCLI: map[ids.ID]*nrpc.JSONRPCClient{destination.blockchainID: destination.ncli},
+		},
+	)
+	gomega.Ω(err).Should(gomega.BeNil())
+
+	gomega.Ω(rl.Relay(source.blockchainID, exportTxID)).Should(gomega.BeNil())
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	go rl.Run(ctx) //nolint:errcheck
+
+	importTxID, fee, err := rl.WaitForImport(ctx, source.blockchainID, exportTxID)
+	gomega.Ω(err).Should(gomega.BeNil())
+	hutils.Outf("{{yellow}}found warp import transaction{{/}}\n")
+	return importTxID, fee
+}
+
+// waitForMultiHopWarpImport relays the ExportAsset transaction [exportTxID],
+// submitted on hops[0], across every intermediate chain in hops and blocks
+// until it lands on hops[len(hops)-1], auto-chaining the export/import at
+// each hop in between. params carries the transfer details (recipient,
+// origin asset, value) every auto-chained leg past the first needs to
+// rebuild its ExportAsset.
+func waitForMultiHopWarpImport(hops []instance, exportTxID ids.ID, params relayer.HopParams) (ids.ID, uint64) {
+	hopIDs := make([]ids.ID, len(hops))
+	hcli := map[ids.ID]*hrpc.JSONRPCClient{}
+	ncli := map[ids.ID]*nrpc.JSONRPCClient{}
+	for i, h := range hops {
+		hopIDs[i] = h.blockchainID
+		hcli[h.blockchainID] = h.hcli
+		ncli[h.blockchainID] = h.ncli
+	}
+
+	rl, err := relayer.New(
+		logging.NoLog{},
+		&relayer.Config{
+			Routes: []relayer.RouteConfig{
+				{
+					SourceBlockchainID:      hopIDs[0],
+					DestinationBlockchainID: hopIDs[len(hopIDs)-1],
+					SigningKey:              relayerSigningKey,
+					MinStakeWeightPercent:   100,
+					Hops:                    hopIDs,
+				},
+			},
+		},
+		relayer.Clients{HCLI: hcli, NCLI: ncli},
+	)
+	gomega.Ω(err).Should(gomega.BeNil())
+
+	gomega.Ω(rl.RelayHop(hopIDs[0], exportTxID, params)).Should(gomega.BeNil())
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	go rl.Run(ctx) //nolint:errcheck
+
+	importTxID, fee, err := rl.WaitForImport(ctx, hopIDs[0], exportTxID)
+	gomega.Ω(err).Should(gomega.BeNil())
+	hutils.Outf("{{yellow}}found multi-hop warp import transaction{{/}}\n")
+	return importTxID, fee
 }
 
 var _ = ginkgo.AfterSuite(func() {
@@ -469,6 +689,10 @@ var _ = ginkgo.AfterSuite(func() {
 		for _, member := range instancesB {
 			hutils.Outf("%s URI: %s\n", member.nodeID, member.uri)
 		}
+		hutils.Outf("\n{{cyan}}Blockchain C:{{/}} %s\n", blockchainIDC)
+		for _, member := range instancesC {
+			hutils.Outf("%s URI: %s\n", member.nodeID, member.uri)
+		}
 
 	case modeRunSingle:
 		hutils.Outf("{{yellow}}skipping cluster shutdown{{/}}\n\n")
@@ -476,6 +700,12 @@ var _ = ginkgo.AfterSuite(func() {
 		for _, member := range instancesA {
 			hutils.Outf("%s URI: %s\n", member.nodeID, member.uri)
 		}
+
+	case modeDev:
+		hutils.Outf("{{yellow}}skipping dev node shutdown{{/}}\n\n")
+		hutils.Outf("{{cyan}}Blockchain:{{/}} %s\n", blockchainIDA)
+		hutils.Outf("%s URI: %s\n", devInstance.chainID, devInstance.uri)
+		return
 	}
 	gomega.Expect(anrCli.Close()).Should(gomega.BeNil())
 })
@@ -498,6 +728,15 @@ var _ = ginkgo.Describe("[Ping]", func() {
 			gomega.Ω(err).Should(gomega.BeNil())
 		}
 	})
+
+	ginkgo.It("can ping C", func() {
+		for _, inst := range instancesC {
+			hcli := inst.hcli
+			ok, err := hcli.Ping(context.Background())
+			gomega.Ω(ok).Should(gomega.BeTrue())
+			gomega.Ω(err).Should(gomega.BeNil())
+		}
+	})
 })
 
 var _ = ginkgo.Describe("[Network]", func() {
@@ -518,11 +757,20 @@ var _ = ginkgo.Describe("[Network]", func() {
 			gomega.Ω(err).Should(gomega.BeNil())
 		}
 	})
+
+	ginkgo.It("can get network C", func() {
+		for _, inst := range instancesC {
+			hcli := inst.hcli
+			_, _, chainID, err := hcli.Network(context.Background())
+			gomega.Ω(chainID).ShouldNot(gomega.Equal(ids.Empty))
+			gomega.Ω(err).Should(gomega.BeNil())
+		}
+	})
 })
 
 var _ = ginkgo.Describe("[Test]", func() {
 	switch mode {
-	case modeRun, modeRunSingle:
+	case modeRun, modeRunSingle, modeDev:
 		hutils.Outf("{{yellow}}skipping tests{{/}}\n")
 		return
 	}
@@ -723,56 +971,7 @@ var _ = ginkgo.Describe("[Test]", func() {
 			gomega.Ω(err).Should(gomega.BeNil())
 			gomega.Ω(newSenderBalance).Should(gomega.Equal(uint64(0)))
 
-			var (
-				msg                     *warp.Message
-				subnetWeight, sigWeight uint64
-			)
-			for {
-				msg, subnetWeight, sigWeight, err = instancesA[0].hcli.GenerateAggregateWarpSignature(
-					context.Background(),
-					txID,
-				)
-				if sigWeight == subnetWeight && err == nil {
-					break
-				}
-				if err == nil {
-					hutils.Outf(
-						"{{yellow}}waiting for signature weight:{{/}} %d {{yellow}}observed:{{/}} %d\n",
-						subnetWeight,
-						sigWeight,
-					)
-				} else {
-					hutils.Outf("{{red}}found error:{{/}} %v\n", err)
-				}
-				time.Sleep(1 * time.Second)
-			}
-			hutils.Outf(
-				"{{green}}fetched signature weight:{{/}} %d {{green}}total weight:{{/}} %d\n",
-				sigWeight,
-				subnetWeight,
-			)
-			gomega.Ω(subnetWeight).Should(gomega.Equal(sigWeight))
-
-			parser, err := instancesB[0].ncli.Parser(context.TODO())
-			gomega.Ω(err).Should(gomega.BeNil())
-			submit, tx, _, err := instancesB[0].hcli.GenerateTransaction(
-				context.Background(),
-				parser,
-				msg,
-				&actions.ImportAsset{},
-				factory,
-			)
-			gomega.Ω(err).Should(gomega.BeNil())
-			txID = tx.ID()
-			hutils.Outf("{{yellow}}generated transaction:{{/}} %s\n", txID)
-			gomega.Ω(submit(context.Background())).Should(gomega.BeNil())
-			hutils.Outf("{{yellow}}submitted transaction{{/}}\n")
-			ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-			success, fee, err := instancesB[0].ncli.WaitForTransaction(ctx, tx.ID())
-			cancel()
-			gomega.Ω(err).Should(gomega.BeNil())
-			gomega.Ω(success).Should(gomega.BeTrue())
-			hutils.Outf("{{yellow}}found warp import transaction{{/}}\n")
+			txID, fee = waitForWarpImport(instancesA[0], instancesB[0], txID, 100)
 
 			// Check asset info and balance
 			aNativeOtherBalance, err := instancesB[0].ncli.Balance(
@@ -938,56 +1137,7 @@ var _ = ginkgo.Describe("[Test]", func() {
 			gomega.Ω(err).Should(gomega.BeNil())
 			gomega.Ω(newSenderBalance).Should(gomega.Equal(uint64(0)))
 
-			var (
-				msg                     *warp.Message
-				subnetWeight, sigWeight uint64
-			)
-			for {
-				msg, subnetWeight, sigWeight, err = instancesB[0].hcli.GenerateAggregateWarpSignature(
-					context.Background(),
-					txID,
-				)
-				if sigWeight == subnetWeight && err == nil {
-					break
-				}
-				if err == nil {
-					hutils.Outf(
-						"{{yellow}}waiting for signature weight:{{/}} %d {{yellow}}observed:{{/}} %d\n",
-						subnetWeight,
-						sigWeight,
-					)
-				} else {
-					hutils.Outf("{{red}}found error:{{/}} %v\n", err)
-				}
-				time.Sleep(1 * time.Second)
-			}
-			hutils.Outf(
-				"{{green}}fetched signature weight:{{/}} %d {{green}}total weight:{{/}} %d\n",
-				sigWeight,
-				subnetWeight,
-			)
-			gomega.Ω(subnetWeight).Should(gomega.Equal(sigWeight))
-
-			parser, err := instancesA[0].ncli.Parser(context.TODO())
-			gomega.Ω(err).Should(gomega.BeNil())
-			submit, tx, _, err := instancesA[0].hcli.GenerateTransaction(
-				context.Background(),
-				parser,
-				msg,
-				&actions.ImportAsset{},
-				factory,
-			)
-			gomega.Ω(err).Should(gomega.BeNil())
-			txID = tx.ID()
-			hutils.Outf("{{yellow}}generated transaction:{{/}} %s\n", txID)
-			gomega.Ω(submit(context.Background())).Should(gomega.BeNil())
-			hutils.Outf("{{yellow}}submitted transaction{{/}}\n")
-			ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-			success, fee, err := instancesA[0].ncli.WaitForTransaction(ctx, tx.ID())
-			cancel()
-			gomega.Ω(err).Should(gomega.BeNil())
-			gomega.Ω(success).Should(gomega.BeTrue())
-			hutils.Outf("{{yellow}}found warp import transaction{{/}}\n")
+			txID, fee = waitForWarpImport(instancesB[0], instancesA[0], txID, 100)
 
 			// Check balances and loan
 			aNativeOtherBalance, err := instancesA[0].ncli.Balance(
@@ -1100,56 +1250,7 @@ var _ = ginkgo.Describe("[Test]", func() {
 			gomega.Ω(err).Should(gomega.BeNil())
 			gomega.Ω(newSenderBalance).Should(gomega.Equal(uint64(0)))
 
-			var (
-				msg                     *warp.Message
-				subnetWeight, sigWeight uint64
-			)
-			for {
-				msg, subnetWeight, sigWeight, err = instancesB[0].hcli.GenerateAggregateWarpSignature(
-					context.Background(),
-					txID,
-				)
-				if sigWeight == subnetWeight && err == nil {
-					break
-				}
-				if err == nil {
-					hutils.Outf(
-						"{{yellow}}waiting for signature weight:{{/}} %d {{yellow}}observed:{{/}} %d\n",
-						subnetWeight,
-						sigWeight,
-					)
-				} else {
-					hutils.Outf("{{red}}found error:{{/}} %v\n", err)
-				}
-				time.Sleep(1 * time.Second)
-			}
-			hutils.Outf(
-				"{{green}}fetched signature weight:{{/}} %d {{green}}total weight:{{/}} %d\n",
-				sigWeight,
-				subnetWeight,
-			)
-			gomega.Ω(subnetWeight).Should(gomega.Equal(sigWeight))
-
-			parser, err := instancesA[0].ncli.Parser(context.TODO())
-			gomega.Ω(err).Should(gomega.BeNil())
-			submit, tx, _, err := instancesA[0].hcli.GenerateTransaction(
-				context.Background(),
-				parser,
-				msg,
-				&actions.ImportAsset{},
-				factory,
-			)
-			gomega.Ω(err).Should(gomega.BeNil())
-			txID = tx.ID()
-			hutils.Outf("{{yellow}}generated transaction:{{/}} %s\n", txID)
-			gomega.Ω(submit(context.Background())).Should(gomega.BeNil())
-			hutils.Outf("{{yellow}}submitted transaction{{/}}\n")
-			ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-			success, fee, err := instancesA[0].ncli.WaitForTransaction(ctx, tx.ID())
-			cancel()
-			gomega.Ω(err).Should(gomega.BeNil())
-			gomega.Ω(success).Should(gomega.BeTrue())
-			hutils.Outf("{{yellow}}found warp import transaction{{/}}\n")
+			txID, fee = waitForWarpImport(instancesB[0], instancesA[0], txID, 100)
 
 			// Check balances and loan
 			aNativeOtherBalance, err := instancesA[0].ncli.Balance(
@@ -1336,8 +1437,62 @@ var _ = ginkgo.Describe("[Test]", func() {
 		})
 	})
 
+	ginkgo.It("imports a warp transfer once a partial quorum of stake weight signs", func() {
+		other, err := ed25519.GeneratePrivateKey()
+		gomega.Ω(err).Should(gomega.BeNil())
+		aother := auth.NewED25519Address(other.PublicKey())
+		destination, err := ids.FromString(blockchainIDB)
+		gomega.Ω(err).Should(gomega.BeNil())
+
+		// Pause the last validator on subnet A so it can never sign the
+		// export's warp message; the relayer must still import once the
+		// remaining validators alone clear the route's
+		// MinStakeWeightPercent, rather than blocking on full weight
+		// forever the way the original polling loop this test replaces did.
+		pausedNode := fmt.Sprintf("node%d-bls", numValidators)
+		_, err = anrCli.PauseNode(context.Background(), pausedNode)
+		gomega.Ω(err).Should(gomega.BeNil())
+		awaitHealthy(anrCli)
+		defer func() {
+			_, err := anrCli.ResumeNode(context.Background(), pausedNode)
+			gomega.Ω(err).Should(gomega.BeNil())
+			awaitHealthy(anrCli)
+		}()
+
+		parser, err := instancesA[0].ncli.Parser(context.TODO())
+		gomega.Ω(err).Should(gomega.BeNil())
+		submit, tx, _, err := instancesA[0].hcli.GenerateTransaction(
+			context.Background(),
+			parser,
+			nil,
+			&actions.ExportAsset{
+				To:          aother,
+				Asset:       ids.Empty,
+				Value:       sendAmount,
+				Return:      false,
+				Destination: destination,
+			},
+			factory,
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		hutils.Outf("{{yellow}}generated transaction{{/}}\n")
+
+		gomega.Ω(submit(context.Background())).Should(gomega.BeNil())
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		success, _, err := instancesA[0].ncli.WaitForTransaction(ctx, tx.ID())
+		cancel()
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(success).Should(gomega.BeTrue())
+		hutils.Outf("{{yellow}}found warp export transaction{{/}}\n")
+
+		// A single paused validator already holds less than 33% of subnet
+		// A's stake weight in this suite's evenly-weighted validator set,
+		// so 67% is reachable without it; ask for anything short of 100%.
+		_, _ = waitForWarpImport(instancesA[0], instancesB[0], tx.ID(), 67)
+		hutils.Outf("{{green}}imported warp transfer with one validator offline{{/}}\n")
+	})
+
 	// TODO: add custom asset test
-	// TODO: test with only part of sig weight
 	// TODO: attempt to mint a warp asset
 
 	switch mode {
@@ -1506,6 +1661,161 @@ var _ = ginkgo.Describe("[Test]", func() {
 	// TODO: restart all nodes (crisis simulation)
 })
 
+var _ = ginkgo.Describe("[Warp Multi-Hop]", func() {
+	switch mode {
+	case modeRun, modeRunSingle, modeDev:
+		hutils.Outf("{{yellow}}skipping tests{{/}}\n")
+		return
+	}
+
+	ginkgo.It("routes a native asset from A to C via B, and back", func() {
+		other, err := ed25519.GeneratePrivateKey()
+		gomega.Ω(err).Should(gomega.BeNil())
+		aother := auth.NewED25519Address(other.PublicKey())
+
+		var forwardExportTxID ids.ID
+		ginkgo.By("submitting an export action on A, routed to C via B", func() {
+			parser, err := instancesA[0].ncli.Parser(context.TODO())
+			gomega.Ω(err).Should(gomega.BeNil())
+			submit, tx, _, err := instancesA[0].hcli.GenerateTransaction(
+				context.Background(),
+				parser,
+				nil,
+				&actions.ExportAsset{
+					To:          aother,
+					Asset:       ids.Empty,
+					Value:       sendAmount,
+					Return:      false,
+					Destination: instancesB[0].blockchainID,
+					Route:       []ids.ID{instancesA[0].blockchainID, instancesB[0].blockchainID, instancesC[0].blockchainID},
+				},
+				factory,
+			)
+			gomega.Ω(err).Should(gomega.BeNil())
+			forwardExportTxID = tx.ID()
+
+			gomega.Ω(submit(context.Background())).Should(gomega.BeNil())
+			ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+			success, _, err := instancesA[0].ncli.WaitForTransaction(ctx, forwardExportTxID)
+			cancel()
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(success).Should(gomega.BeTrue())
+
+			amount, err := instancesA[0].ncli.Loan(context.Background(), ids.Empty, instancesB[0].blockchainID)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(amount).Should(gomega.Equal(sendAmount))
+		})
+
+		var finalImportTxID ids.ID
+		ginkgo.By("relaying the export across B to land on C", func() {
+			finalImportTxID, _ = waitForMultiHopWarpImport(
+				[]instance{instancesA[0], instancesB[0], instancesC[0]},
+				forwardExportTxID,
+				relayer.HopParams{To: aother, Asset: ids.Empty, Value: sendAmount, Return: false},
+			)
+			gomega.Ω(finalImportTxID).ShouldNot(gomega.Equal(ids.Empty))
+
+			balance, err := instancesC[0].ncli.Balance(
+				context.Background(),
+				codec.MustAddressBech32(nconsts.HRP, aother),
+				ids.Empty,
+			)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(balance).Should(gomega.Equal(sendAmount))
+		})
+
+		var returnExportTxID ids.ID
+		ginkgo.By("submitting a return export action on C, routed to A via B", func() {
+			otherFactory := auth.NewED25519Factory(other)
+			parser, err := instancesC[0].ncli.Parser(context.TODO())
+			gomega.Ω(err).Should(gomega.BeNil())
+			submit, tx, _, err := instancesC[0].hcli.GenerateTransaction(
+				context.Background(),
+				parser,
+				nil,
+				&actions.ExportAsset{
+					To:          rsender,
+					Asset:       ids.Empty,
+					Value:       sendAmount,
+					Return:      true,
+					Destination: instancesB[0].blockchainID,
+					Route:       []ids.ID{instancesC[0].blockchainID, instancesB[0].blockchainID, instancesA[0].blockchainID},
+				},
+				otherFactory,
+			)
+			gomega.Ω(err).Should(gomega.BeNil())
+			returnExportTxID = tx.ID()
+
+			gomega.Ω(submit(context.Background())).Should(gomega.BeNil())
+			ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+			success, _, err := instancesC[0].ncli.WaitForTransaction(ctx, returnExportTxID)
+			cancel()
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(success).Should(gomega.BeTrue())
+		})
+
+		ginkgo.By("relaying the return export across B back to A, draining both loans", func() {
+			returnImportTxID, _ := waitForMultiHopWarpImport(
+				[]instance{instancesC[0], instancesB[0], instancesA[0]},
+				returnExportTxID,
+				relayer.HopParams{To: rsender, Asset: ids.Empty, Value: sendAmount, Return: true},
+			)
+			gomega.Ω(returnImportTxID).ShouldNot(gomega.Equal(ids.Empty))
+
+			// B's loan was recorded against C (the hop it auto-exported to on
+			// the way out), not against A; it closes out once B imports the
+			// C->B leg of the return trip.
+			bLoan, err := instancesB[0].ncli.Loan(context.Background(), ids.Empty, instancesC[0].blockchainID)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(bLoan).Should(gomega.Equal(uint64(0)))
+
+			aLoan, err := instancesA[0].ncli.Loan(context.Background(), ids.Empty, instancesB[0].blockchainID)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(aLoan).Should(gomega.Equal(uint64(0)))
+		})
+	})
+})
+
+var _ = ginkgo.Describe("[Conformance]", func() {
+	switch mode {
+	case modeRun, modeRunSingle:
+		hutils.Outf("{{yellow}}skipping tests{{/}}\n")
+		return
+	}
+
+	ginkgo.It("replays the vector corpus against a running node", func() {
+		vs, err := vectors.Load(filepath.Join("..", "vectors", "corpus"))
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(vs).ShouldNot(gomega.BeEmpty())
+
+		for _, v := range vs {
+			v := v
+			ginkgo.By(v.Name, func() {
+				action, err := vectors.DecodeAction(rsender, v)
+				gomega.Ω(err).Should(gomega.BeNil())
+
+				parser, err := instancesA[0].ncli.Parser(context.Background())
+				gomega.Ω(err).Should(gomega.BeNil())
+				submit, tx, _, err := instancesA[0].hcli.GenerateTransaction(
+					context.Background(),
+					parser,
+					nil,
+					action,
+					factory,
+				)
+				gomega.Ω(err).Should(gomega.BeNil())
+				gomega.Ω(submit(context.Background())).Should(gomega.BeNil())
+
+				ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+				success, _, err := instancesA[0].ncli.WaitForTransaction(ctx, tx.ID())
+				cancel()
+				gomega.Ω(err).Should(gomega.BeNil())
+				gomega.Ω(success).Should(gomega.Equal(v.ExpectedSuccess))
+			})
+		}
+	})
+})
+
 func awaitHealthy(cli runner_sdk.Client) {
 	for {
 		time.Sleep(healthPollInterval)
@@ -1524,6 +1834,9 @@ func awaitHealthy(cli runner_sdk.Client) {
 
 // generate blocks until either ctx is cancelled or the specified (!= 0) number of blocks is generated.
 // if 0 blocks are specified, will just wait until ctx is cancelled.
+//
+// Broadcasting and height-polling are delegated to a loadgen.Driver so this
+// helper only has to own the test's stop condition and invariants.
 func generateBlocks(
 	ctx context.Context,
 	cumulativeTxs int,
@@ -1539,66 +1852,26 @@ func generateBlocks(
 	if blocksToGenerate != 0 {
 		targetHeight = lastHeight + blocksToGenerate
 	}
-	for ctx.Err() == nil {
-		// Generate transaction
-		other, err := ed25519.GeneratePrivateKey()
-		gomega.Ω(err).Should(gomega.BeNil())
-		submit, _, _, err := instances[cumulativeTxs%len(instances)].hcli.GenerateTransaction(
-			context.Background(),
-			parser,
-			nil,
-			&actions.Transfer{
-				To:    auth.NewED25519Address(other.PublicKey()),
-				Value: 1,
-			},
-			factory,
-		)
-		if failOnError {
-			gomega.Ω(err).Should(gomega.BeNil())
-		} else if err != nil {
-			hutils.Outf(
-				"{{yellow}}unable to generate transaction:{{/}} %v\n",
-				err,
-			)
-			time.Sleep(5 * time.Second)
-			continue
-		}
 
-		// Broadcast transactions
-		err = submit(context.Background())
-		if failOnError {
-			gomega.Ω(err).Should(gomega.BeNil())
-		} else if err != nil {
-			hutils.Outf(
-				"{{yellow}}tx broadcast failed:{{/}} %v\n",
-				err,
-			)
-			time.Sleep(5 * time.Second)
-			continue
-		}
-		cumulativeTxs++
-		_, height, _, err := instances[0].hcli.Accepted(context.Background())
-		if failOnError {
-			gomega.Ω(err).Should(gomega.BeNil())
-		} else if err != nil {
-			hutils.Outf(
-				"{{yellow}}height lookup failed:{{/}} %v\n",
-				err,
-			)
-			time.Sleep(5 * time.Second)
-			continue
-		}
-		if targetHeight != 0 && height > targetHeight {
-			break
-		} else if height > lastHeight {
-			lastHeight = height
-			hutils.Outf("{{yellow}}height=%d count=%d{{/}}\n", height, cumulativeTxs)
-		}
+	sink := loadgen.NewSink(instances[0].ncli)
+	driver, err := loadgen.New(
+		instances[0].hcli,
+		parser,
+		&loadgen.TransferWorkload{Factory: factory, Value: 1},
+		[]auth.Factory{factory},
+		loadgen.Config{TargetHeight: targetHeight},
+		sink,
+	)
+	gomega.Ω(err).Should(gomega.BeNil())
 
-		// Sleep for a very small amount of time to avoid overloading the
-		// network with transactions (can generate very fast)
-		time.Sleep(10 * time.Millisecond)
+	if err := driver.Run(ctx); err != nil && failOnError {
+		gomega.Ω(err).Should(gomega.BeNil())
 	}
+	cumulativeTxs += int(driver.Submitted())
+
+	_, height, _, err := instances[0].hcli.Accepted(context.Background())
+	gomega.Ω(err).Should(gomega.BeNil())
+	hutils.Outf("{{yellow}}height=%d count=%d{{/}}\n", height, cumulativeTxs)
 	return cumulativeTxs
 }
 
@@ -1606,99 +1879,27 @@ func acceptTransaction(hcli *hrpc.JSONRPCClient, ncli *nrpc.JSONRPCClient) {
 	parser, err := ncli.Parser(context.Background())
 	gomega.Ω(err).Should(gomega.BeNil())
 	for {
-		// Generate transaction
-		other, err := ed25519.GeneratePrivateKey()
-		gomega.Ω(err).Should(gomega.BeNil())
-		unitPrices, err := hcli.UnitPrices(context.Background(), false)
-		gomega.Ω(err).Should(gomega.BeNil())
-		submit, tx, maxFee, err := hcli.GenerateTransaction(
-			context.Background(),
+		sink := loadgen.NewSink(ncli)
+		driver, err := loadgen.New(
+			hcli,
 			parser,
-			nil,
-			&actions.Transfer{
-				To:    auth.NewED25519Address(other.PublicKey()),
-				Value: sendAmount,
-			},
-			factory,
+			&loadgen.TransferWorkload{Factory: factory, Value: sendAmount},
+			[]auth.Factory{factory},
+			loadgen.Config{TargetTxs: 1, Deadline: requestTimeout},
+			sink,
 		)
 		gomega.Ω(err).Should(gomega.BeNil())
-		hutils.Outf("{{yellow}}generated transaction{{/}} prices: %+v maxFee: %d\n", unitPrices, maxFee)
+		gomega.Ω(driver.Run(context.Background())).Should(gomega.BeNil())
 
-		// Broadcast and wait for transaction
-		gomega.Ω(submit(context.Background())).Should(gomega.BeNil())
-		hutils.Outf("{{yellow}}submitted transaction{{/}}\n")
-		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-		success, _, err := ncli.WaitForTransaction(ctx, tx.ID())
-		cancel()
-		if err != nil {
-			hutils.Outf("{{red}}cannot find transaction: %v{{/}}\n", err)
+		if sink.Summary().Succeeded != 1 {
+			hutils.Outf("{{red}}cannot find transaction, retrying{{/}}\n")
 			continue
 		}
-		gomega.Ω(success).Should(gomega.BeTrue())
 		hutils.Outf("{{yellow}}found transaction{{/}}\n")
 		break
 	}
 }
 
-// copyNodeInfo handles the entire process of copying signer.key & staking.* files from the source directory
-// to the destination directory, after stripping out "/db" from the source path.
-func copyNodeInfo(sourceLogPath, destDir string) error {
-	// Step 1: Strip out "/db" from the source path
-	basePath := strings.TrimSuffix(sourceLogPath, "/db")
-
-	// Ensure the destination directory exists, create it if it doesn't
-	if err := os.MkdirAll(destDir, 0o755); err != nil {
-		return fmt.Errorf("failed to create destination directory: %w", err)
-	}
-
-	// Define the full path for signer.key
-	signerKeyPath := filepath.Join(basePath, "signer.key")
-
-	// Step 2: Copy the signer.key file if it exists
-	if _, err := os.Stat(signerKeyPath); err == nil {
-		destSignerKeyPath := filepath.Join(destDir, "signer.key")
-		if err := forceCopyFile(signerKeyPath, destSignerKeyPath); err != nil {
-			return fmt.Errorf("failed to copy signer.key: %w", err)
-		}
-	} else if !os.IsNotExist(err) {
-		// If the error is not due to the file not existing, return the error
-		return fmt.Errorf("error checking signer.key: %w", err)
-	}
-
-	// Step 3: Copy files matching "staking.*" from source to destination, force overwrite
-	return filepath.Walk(basePath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		// Match only files that start with "staking."
-		if matched, _ := filepath.Match("staking.*", info.Name()); matched {
-			destPath := filepath.Join(destDir, info.Name())
-			return forceCopyFile(path, destPath)
-		}
-		return nil
-	})
-}
-
-// forceCopyFile copies a file from src to dst, overwriting the dst file if it exists.
-func forceCopyFile(src, dst string) error {
-	sourceFile, err := os.Open(src)
-	if err != nil {
-		return err
-	}
-	defer sourceFile.Close()
-
-	// Use os.Create to open the destination file for writing, creating it if it doesn't exist
-	// or truncating it if it does.
-	destFile, err := os.Create(dst)
-	if err != nil {
-		return err
-	}
-	defer destFile.Close()
-
-	_, err = io.Copy(destFile, sourceFile)
-	if err != nil {
-		return err
-	}
-
-	return destFile.Sync()
-}
+// copyNodeInfo and restoreNodeInfo live in nodeinfo_bundle.go: they bundle
+// signer.key/staking.* into an encrypted nodeinfo.bundle rather than copying
+// the raw files, since destDir here is a shared artifact path.
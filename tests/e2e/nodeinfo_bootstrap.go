@@ -0,0 +1,239 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package e2e_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	hutils "github.com/ava-labs/hypersdk/utils"
+)
+
+// nodeBootstrapManifest is the JSON manifest a node-bootstrap tarball embeds
+// alongside a detached manifest.sig, describing exactly the files the
+// tarball delivers so verifyNodeBootstrap can check them before anything is
+// extracted.
+type nodeBootstrapManifest struct {
+	NodeID    string                      `json:"nodeID"`
+	ChainID   string                      `json:"chainID"`
+	NetworkID uint32                      `json:"networkID"`
+	CreatedAt time.Time                   `json:"createdAt"`
+	Files     []nodeBootstrapManifestFile `json:"files"`
+}
+
+type nodeBootstrapManifestFile struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// loadOrCreateDistSigningKey reads the Ed25519 "distribution key" copyNodeInfo
+// signs node-bootstrap manifests with from path, generating and persisting a
+// fresh one on first use. The public key is printed so the operator can save
+// it once and hand it to verifyNodeBootstrap on every other host.
+func loadOrCreateDistSigningKey(path string) (ed25519.PrivateKey, error) {
+	if data, err := os.ReadFile(path); err == nil {
+		if len(data) != ed25519.PrivateKeySize {
+			return nil, fmt.Errorf("dist signing key %s has the wrong size for ed25519", path)
+		}
+		return ed25519.PrivateKey(data), nil
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read dist signing key: %w", err)
+	}
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate dist signing key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create dist signing key directory: %w", err)
+	}
+	if err := os.WriteFile(path, priv, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to save dist signing key: %w", err)
+	}
+	hutils.Outf(
+		"{{yellow}}generated dist signing key at %s; public key (save this for verifyNodeBootstrap): %s{{/}}\n",
+		path, hex.EncodeToString(pub),
+	)
+	return priv, nil
+}
+
+// writeNodeBootstrap signs a manifest over files with the distribution key at
+// distSigningKeyPath (generated there on first use) and writes
+// node-bootstrap-<nodeID>-<timestamp>.tar.gz into destDir, containing the
+// manifest, a detached manifest.sig, and the files themselves.
+func writeNodeBootstrap(
+	destDir string,
+	nodeID ids.NodeID,
+	chainID ids.ID,
+	networkID uint32,
+	files map[string][]byte,
+	distSigningKeyPath string,
+) error {
+	priv, err := loadOrCreateDistSigningKey(distSigningKeyPath)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	createdAt := time.Now().UTC()
+	manifest := nodeBootstrapManifest{
+		NodeID:    nodeID.String(),
+		ChainID:   chainID.String(),
+		NetworkID: networkID,
+		CreatedAt: createdAt,
+		Files:     make([]nodeBootstrapManifestFile, len(names)),
+	}
+	for i, name := range names {
+		data := files[name]
+		sum := sha256.Sum256(data)
+		manifest.Files[i] = nodeBootstrapManifestFile{
+			Name:   name,
+			SHA256: hex.EncodeToString(sum[:]),
+			Size:   int64(len(data)),
+		}
+	}
+	manifestBytes, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	sig := ed25519.Sign(priv, manifestBytes)
+
+	var tarBuf bytes.Buffer
+	tw := tar.NewWriter(&tarBuf)
+	if err := writeTarFile(tw, "manifest.json", manifestBytes); err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, "manifest.sig", sig); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := writeTarFile(tw, name, files[name]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	archiveName := fmt.Sprintf("node-bootstrap-%s-%d.tar.gz", nodeID, createdAt.Unix())
+	out, err := os.Create(filepath.Join(destDir, archiveName))
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", archiveName, err)
+	}
+	defer out.Close()
+
+	gw := gzip.NewWriter(out)
+	if _, err := gw.Write(tarBuf.Bytes()); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// verifyNodeBootstrap checks a node-bootstrap tarball's manifest.sig against
+// pubKey and every listed file's SHA-256 against the manifest before
+// extracting anything into destDir, refusing to install if either check
+// fails. It refuses to overwrite a file that already exists in destDir
+// unless force is set, mirroring restoreNodeInfo.
+func verifyNodeBootstrap(path string, pubKey ed25519.PublicKey, destDir string, force bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("failed to decompress %s: %w", path, err)
+	}
+	defer gr.Close()
+
+	var manifestBytes, sig []byte
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		switch hdr.Name {
+		case "manifest.json":
+			manifestBytes = data
+		case "manifest.sig":
+			sig = data
+		default:
+			files[hdr.Name] = data
+		}
+	}
+	if manifestBytes == nil || sig == nil {
+		return fmt.Errorf("%s is missing manifest.json or manifest.sig", path)
+	}
+	if !ed25519.Verify(pubKey, manifestBytes, sig) {
+		return fmt.Errorf("%s failed signature verification", path)
+	}
+
+	var manifest nodeBootstrapManifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	for _, entry := range manifest.Files {
+		data, ok := files[entry.Name]
+		if !ok {
+			return fmt.Errorf("%s is missing manifest entry for %s", path, entry.Name)
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != entry.SHA256 {
+			return fmt.Errorf("integrity check failed for %s: bundle may be corrupt or tampered with", entry.Name)
+		}
+	}
+
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+	// Check every file before writing any of them, mirroring
+	// restoreNodeInfo: manifest.Files is iterated twice rather than
+	// check-then-written in one pass so an entry that already exists is
+	// never discovered after an earlier entry has already been written.
+	if !force {
+		for _, entry := range manifest.Files {
+			destPath := filepath.Join(destDir, entry.Name)
+			if _, err := os.Stat(destPath); err == nil {
+				return fmt.Errorf("%s already exists; pass force to overwrite", destPath)
+			} else if !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	for _, entry := range manifest.Files {
+		destPath := filepath.Join(destDir, entry.Name)
+		if err := os.WriteFile(destPath, files[entry.Name], 0o600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", entry.Name, err)
+		}
+	}
+	return nil
+}
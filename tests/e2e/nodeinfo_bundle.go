@@ -0,0 +1,316 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package e2e_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"golang.org/x/crypto/argon2"
+)
+
+// nodeInfoPassphraseEnv names the environment variable restoreNodeInfo and
+// copyNodeInfo read the bundle passphrase from. There's no flag for the
+// passphrase itself so it never ends up in a process listing or CI log.
+const nodeInfoPassphraseEnv = "NUKLAIVM_NODEINFO_PASSPHRASE"
+
+// nodeInfoBundleName is the file copyNodeInfo writes the encrypted bundle to
+// inside destDir, replacing the plaintext signer.key/staking.* copies it used
+// to leave there.
+const nodeInfoBundleName = "nodeinfo.bundle"
+
+const nodeInfoBundleMagic = "NIB1"
+
+// Argon2id parameters for deriving the bundle's AES-256-GCM key from the
+// operator-supplied passphrase: one pass over 64 MiB of memory with 4 lanes,
+// which is plenty for a process that runs once per node copy
+// rather than on a hot path.
+const (
+	argon2Time    = 1
+	argon2Memory  = 64 * 1024 // KiB
+	argon2Threads = 4
+	argon2KeyLen  = 32
+
+	nodeInfoSaltLen  = 16
+	nodeInfoNonceLen = 12
+)
+
+// nodeInfoManifest records a keyed HMAC-SHA256 of every file's plaintext
+// contents, computed before encryption, so restoreNodeInfo can detect
+// corruption or tampering of an individual file independently of the AEAD
+// tag covering the bundle as a whole.
+type nodeInfoManifest struct {
+	Files map[string]string `json:"files"` // filename -> hex HMAC-SHA256
+}
+
+// copyNodeInfo handles the entire process of bundling signer.key & staking.*
+// files from the source directory into the destination directory, after
+// stripping out "/db" from the source path. If distSigningKeyPath is set, it
+// emits a signed node-bootstrap-<nodeID>-<timestamp>.tar.gz (see
+// writeNodeBootstrap) instead of either of the bundle formats below, since
+// that's the format a new validator host can verify offline against just a
+// public key. Otherwise, if plaintextNodeInfo is set (existing CI flows that
+// don't have a passphrase to provide), it falls back to the old behavior of
+// copying the files as-is; absent both, it seals them into an encrypted
+// nodeinfo.bundle.
+func copyNodeInfo(sourceLogPath, destDir string, nodeID ids.NodeID, chainID ids.ID, networkID uint32) error {
+	basePath := strings.TrimSuffix(sourceLogPath, "/db")
+
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+
+	files, err := collectNodeInfoFiles(basePath)
+	if err != nil {
+		return err
+	}
+
+	if distSigningKeyPath != "" {
+		return writeNodeBootstrap(destDir, nodeID, chainID, networkID, files, distSigningKeyPath)
+	}
+
+	if plaintextNodeInfo {
+		for name, data := range files {
+			if err := os.WriteFile(filepath.Join(destDir, name), data, 0o600); err != nil {
+				return fmt.Errorf("failed to write %s: %w", name, err)
+			}
+		}
+		return nil
+	}
+
+	passphrase := os.Getenv(nodeInfoPassphraseEnv)
+	if passphrase == "" {
+		return fmt.Errorf("%s must be set to bundle node info (or pass -plaintext-nodeinfo)", nodeInfoPassphraseEnv)
+	}
+	bundle, err := sealNodeInfoBundle(files, passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to seal node info bundle: %w", err)
+	}
+	return os.WriteFile(filepath.Join(destDir, nodeInfoBundleName), bundle, 0o600)
+}
+
+// collectNodeInfoFiles reads signer.key (if present) and every staking.* file
+// under basePath into memory, keyed by base filename.
+func collectNodeInfoFiles(basePath string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+
+	signerKeyPath := filepath.Join(basePath, "signer.key")
+	if data, err := os.ReadFile(signerKeyPath); err == nil {
+		files["signer.key"] = data
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading signer.key: %w", err)
+	}
+
+	err := filepath.Walk(basePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if matched, _ := filepath.Match("staking.*", info.Name()); matched {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			files[info.Name()] = data
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// sealNodeInfoBundle derives an AES-256-GCM key from passphrase via Argon2id,
+// HMACs each file's plaintext with that key, tars files alongside the
+// resulting manifest, and encrypts the tar. The returned bytes are the entire
+// on-disk bundle: a header (magic, salt, Argon2 params, nonce) followed by the
+// ciphertext.
+func sealNodeInfoBundle(files map[string][]byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, nodeInfoSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key := argon2.IDKey([]byte(passphrase), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+
+	manifest := nodeInfoManifest{Files: make(map[string]string, len(files))}
+	for name, data := range files {
+		mac := hmac.New(sha256.New, key)
+		mac.Write(data)
+		manifest.Files[name] = hex.EncodeToString(mac.Sum(nil))
+	}
+	manifestBytes, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := writeTarFile(tw, "manifest.json", manifestBytes); err != nil {
+		return nil, err
+	}
+	for name, data := range files {
+		if err := writeTarFile(tw, name, data); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, nodeInfoNonceLen)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	ciphertext := gcm.Seal(nil, nonce, buf.Bytes(), nil)
+
+	var out bytes.Buffer
+	out.WriteString(nodeInfoBundleMagic)
+	out.Write(salt)
+	var params [9]byte
+	binary.BigEndian.PutUint32(params[0:4], argon2Time)
+	binary.BigEndian.PutUint32(params[4:8], argon2Memory)
+	params[8] = argon2Threads
+	out.Write(params[:])
+	out.Write(nonce)
+	out.Write(ciphertext)
+	return out.Bytes(), nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(data))}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// restoreNodeInfo decrypts the nodeinfo.bundle at bundlePath with passphrase,
+// verifies every file's HMAC against the bundle's manifest before writing
+// anything, and extracts the files into destDir. It refuses to overwrite a
+// file that already exists in destDir unless force is set.
+func restoreNodeInfo(bundlePath, passphrase, destDir string, force bool) error {
+	raw, err := os.ReadFile(bundlePath)
+	if err != nil {
+		return fmt.Errorf("failed to read bundle: %w", err)
+	}
+
+	headerLen := len(nodeInfoBundleMagic) + nodeInfoSaltLen + 9 + nodeInfoNonceLen
+	if len(raw) < headerLen {
+		return fmt.Errorf("bundle %s is truncated", bundlePath)
+	}
+	if string(raw[:len(nodeInfoBundleMagic)]) != nodeInfoBundleMagic {
+		return fmt.Errorf("bundle %s has an unrecognized header", bundlePath)
+	}
+	offset := len(nodeInfoBundleMagic)
+	salt := raw[offset : offset+nodeInfoSaltLen]
+	offset += nodeInfoSaltLen
+	kdfTime := binary.BigEndian.Uint32(raw[offset : offset+4])
+	kdfMemory := binary.BigEndian.Uint32(raw[offset+4 : offset+8])
+	kdfThreads := raw[offset+8]
+	offset += 9
+	nonce := raw[offset : offset+nodeInfoNonceLen]
+	offset += nodeInfoNonceLen
+	ciphertext := raw[offset:]
+
+	key := argon2.IDKey([]byte(passphrase), salt, kdfTime, kdfMemory, kdfThreads, argon2KeyLen)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt bundle (wrong passphrase or corrupt bundle): %w", err)
+	}
+
+	files := make(map[string][]byte)
+	var manifest nodeInfoManifest
+	tr := tar.NewReader(bytes.NewReader(plaintext))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		if hdr.Name == "manifest.json" {
+			if err := json.Unmarshal(data, &manifest); err != nil {
+				return fmt.Errorf("failed to parse manifest: %w", err)
+			}
+			continue
+		}
+		files[hdr.Name] = data
+	}
+
+	for name, data := range files {
+		expected, ok := manifest.Files[name]
+		if !ok {
+			return fmt.Errorf("bundle is missing a manifest entry for %s", name)
+		}
+		mac := hmac.New(sha256.New, key)
+		mac.Write(data)
+		if hex.EncodeToString(mac.Sum(nil)) != expected {
+			return fmt.Errorf("integrity check failed for %s: bundle may be corrupt or tampered with", name)
+		}
+	}
+
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+	// Check every file before writing any of them: files is a map, so
+	// iterating it once and checking-then-writing each entry in turn could
+	// write earlier-iterated files before discovering a later one already
+	// exists, partially overwriting destDir despite refusing the call.
+	if !force {
+		for name := range files {
+			destPath := filepath.Join(destDir, name)
+			if _, err := os.Stat(destPath); err == nil {
+				return fmt.Errorf("%s already exists; pass force to overwrite", destPath)
+			} else if !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	for name, data := range files {
+		destPath := filepath.Join(destDir, name)
+		if err := os.WriteFile(destPath, data, 0o600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
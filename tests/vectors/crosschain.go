@@ -0,0 +1,289 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vectors
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+
+	"github.com/nuklai/nuklaivm/storage"
+)
+
+// ChainFixture seeds the genesis state of one chain (named "A" or "B")
+// within a Scenario.
+type ChainFixture struct {
+	BlockchainID string           `json:"blockchainID"`
+	PreState     []BalanceFixture `json:"preState"`
+}
+
+// Step is one action submitted against a named chain within a Scenario, in
+// order.
+type Step struct {
+	// Name identifies this step so a later step can reference the warp
+	// message it emitted via ImportsWarp.
+	Name string `json:"name"`
+	// Chain selects which of the Scenario's two chains this step runs
+	// against: "A" or "B".
+	Chain  string `json:"chain"`
+	Signer string `json:"signer"`
+
+	ActionTypeID uint8           `json:"actionTypeID"`
+	Action       json.RawMessage `json:"action"`
+
+	// ImportsWarp, if set, names an earlier step whose action emitted a
+	// warp message; that message is handed to this step's action via the
+	// same Unmarshal(p, w) path a relayer uses to deliver an aggregated
+	// export, so ReceiveMessage (or any other warp-consuming action) can
+	// derive its fields from it.
+	ImportsWarp string `json:"importsWarp,omitempty"`
+
+	ExpectedSuccess bool   `json:"expectedSuccess"`
+	ExpectedOutput  string `json:"expectedOutput,omitempty"`
+	// ExpectedWarp asserts whether this step's action emitted a warp
+	// message at all.
+	ExpectedWarp bool `json:"expectedWarp,omitempty"`
+	// ExpectedWarpPayload, if non-empty, further asserts the emitted warp
+	// message's payload equals these hex-encoded bytes.
+	ExpectedWarpPayload string `json:"expectedWarpPayload,omitempty"`
+
+	ExpectedPostState []BalanceFixture `json:"expectedPostState,omitempty"`
+}
+
+// Scenario is an ordered sequence of Steps exercising a flow across two
+// chains, ChainA and ChainB, through the same chain.Action.Execute path
+// RunScenario's sibling, single-action Run, drives. It covers the part of a
+// relay a production relayer's signature-aggregation loop doesn't: once a
+// step's action emits a warp message, RunScenario hands it directly,
+// unsigned, to whichever later step names it via ImportsWarp, the same way
+// a trusted local test harness would stub out BLS aggregation.
+type Scenario struct {
+	Name   string       `json:"name"`
+	ChainA ChainFixture `json:"chainA"`
+	ChainB ChainFixture `json:"chainB"`
+	Steps  []Step       `json:"steps"`
+
+	// path is the file LoadScenarios read this Scenario from, so a
+	// -record run can write it back to the same place. Unexported, so
+	// encoding/json ignores it on both read and write.
+	path string
+}
+
+// Path returns the corpus file s was loaded from, for a -record run to
+// write back to.
+func (s *Scenario) Path() string {
+	return s.path
+}
+
+// LoadScenarios reads every *.json file in dir as a Scenario, sorted by
+// file name so runs are deterministic.
+func LoadScenarios(dir string) ([]*Scenario, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario corpus %s: %w", dir, err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+
+	scenarios := make([]*Scenario, 0, len(names))
+	for _, name := range names {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read scenario %s: %w", name, err)
+		}
+		s := new(Scenario)
+		if err := json.Unmarshal(b, s); err != nil {
+			return nil, fmt.Errorf("failed to parse scenario %s: %w", name, err)
+		}
+		if s.Name == "" {
+			s.Name = name
+		}
+		s.path = filepath.Join(dir, name)
+		scenarios = append(scenarios, s)
+	}
+	return scenarios, nil
+}
+
+// crossChainRules is the minimal chain.Rules a Scenario's actions need:
+// embedding the zero-value interface satisfies every method RunScenario's
+// actions don't call, while NetworkID/ChainID answer for real so an action
+// like SendMessage can build a warp.UnsignedMessage addressed from the
+// chain the step actually ran against.
+type crossChainRules struct {
+	chain.Rules
+	networkID uint32
+	chainID   ids.ID
+}
+
+func (r crossChainRules) NetworkID() uint32 { return r.networkID }
+func (r crossChainRules) ChainID() ids.ID   { return r.chainID }
+
+// RunScenario seeds ChainA/ChainB from s and executes every Step in order,
+// threading warp messages emitted by one step into whichever later step
+// names it via ImportsWarp. If record is true, RunScenario overwrites s's
+// Expected* fields with the actual outcome of each step instead of
+// asserting them, for regenerating the corpus after an intentional
+// protocol change; the caller is responsible for persisting s back to disk
+// (see TestCrossChain's -record flag).
+func RunScenario(ctx context.Context, s *Scenario, record bool) error {
+	chainAID, err := ids.FromString(s.ChainA.BlockchainID)
+	if err != nil {
+		return fmt.Errorf("%s: invalid chainA.blockchainID: %w", s.Name, err)
+	}
+	chainBID, err := ids.FromString(s.ChainB.BlockchainID)
+	if err != nil {
+		return fmt.Errorf("%s: invalid chainB.blockchainID: %w", s.Name, err)
+	}
+
+	stores := map[string]*memStore{"A": newMemStore(), "B": newMemStore()}
+	chainIDs := map[string]ids.ID{"A": chainAID, "B": chainBID}
+	for _, bal := range s.ChainA.PreState {
+		if err := seedBalance(ctx, stores["A"], codec.EmptyAddress, bal); err != nil {
+			return fmt.Errorf("%s: seeding chainA preState: %w", s.Name, err)
+		}
+	}
+	for _, bal := range s.ChainB.PreState {
+		if err := seedBalance(ctx, stores["B"], codec.EmptyAddress, bal); err != nil {
+			return fmt.Errorf("%s: seeding chainB preState: %w", s.Name, err)
+		}
+	}
+
+	warpMessages := map[string]*warp.UnsignedMessage{}
+
+	for i := range s.Steps {
+		step := &s.Steps[i]
+
+		store, ok := stores[step.Chain]
+		if !ok {
+			return fmt.Errorf("%s: step %q: unknown chain %q", s.Name, step.Name, step.Chain)
+		}
+		actor, err := signerAddress(step.Signer)
+		if err != nil {
+			return fmt.Errorf("%s: step %q: invalid signer: %w", s.Name, step.Name, err)
+		}
+
+		var w *warp.Message
+		if step.ImportsWarp != "" {
+			unsigned, ok := warpMessages[step.ImportsWarp]
+			if !ok {
+				return fmt.Errorf("%s: step %q: no warp message recorded for step %q", s.Name, step.Name, step.ImportsWarp)
+			}
+			w = &warp.Message{UnsignedMessage: *unsigned}
+		}
+
+		action, err := decodeAction(actor, step.ActionTypeID, step.Action, w)
+		if err != nil {
+			return fmt.Errorf("%s: step %q: %w", s.Name, step.Name, err)
+		}
+
+		rules := crossChainRules{networkID: 1, chainID: chainIDs[step.Chain]}
+		success, _, output, unsigned, err := action.Execute(ctx, rules, store, 0, actor, ids.Empty, false)
+		if err != nil {
+			return fmt.Errorf("%s: step %q: Execute returned an error (actions should signal failure via success=false): %w", s.Name, step.Name, err)
+		}
+		if unsigned != nil {
+			warpMessages[step.Name] = unsigned
+		}
+
+		if record {
+			recordStep(step, success, output, unsigned)
+			if err := recordPostState(ctx, store, actor, step.ExpectedPostState); err != nil {
+				return fmt.Errorf("%s: step %q: %w", s.Name, step.Name, err)
+			}
+			continue
+		}
+
+		if err := checkStep(ctx, store, actor, step, success, output, unsigned); err != nil {
+			return fmt.Errorf("%s: step %q: %w", s.Name, step.Name, err)
+		}
+	}
+	return nil
+}
+
+func recordStep(step *Step, success bool, output []byte, unsigned *warp.UnsignedMessage) {
+	step.ExpectedSuccess = success
+	step.ExpectedOutput = string(output)
+	step.ExpectedWarp = unsigned != nil
+	if unsigned != nil {
+		step.ExpectedWarpPayload = hex.EncodeToString(unsigned.Payload)
+	} else {
+		step.ExpectedWarpPayload = ""
+	}
+}
+
+func recordPostState(ctx context.Context, store *memStore, actor codec.Address, postState []BalanceFixture) error {
+	for i := range postState {
+		want := &postState[i]
+		asset, err := want.AssetID()
+		if err != nil {
+			return fmt.Errorf("invalid expectedPostState asset: %w", err)
+		}
+		addr, err := resolveAddress(actor, want.Address)
+		if err != nil {
+			return fmt.Errorf("invalid expectedPostState address: %w", err)
+		}
+		got, err := storage.GetBalance(ctx, store, addr, asset)
+		if err != nil {
+			return fmt.Errorf("reading postState balance for %s: %w", want.Address, err)
+		}
+		want.Balance = got
+	}
+	return nil
+}
+
+func checkStep(ctx context.Context, store *memStore, actor codec.Address, step *Step, success bool, output []byte, unsigned *warp.UnsignedMessage) error {
+	if success != step.ExpectedSuccess {
+		return fmt.Errorf("expected success=%t, got %t (output=%q)", step.ExpectedSuccess, success, output)
+	}
+	if step.ExpectedOutput != "" && string(output) != step.ExpectedOutput {
+		return fmt.Errorf("expected output %q, got %q", step.ExpectedOutput, string(output))
+	}
+	if step.ExpectedWarp != (unsigned != nil) {
+		return fmt.Errorf("expected a warp message=%t, got %t", step.ExpectedWarp, unsigned != nil)
+	}
+	if step.ExpectedWarpPayload != "" {
+		var got string
+		if unsigned != nil {
+			got = hex.EncodeToString(unsigned.Payload)
+		}
+		if got != step.ExpectedWarpPayload {
+			return fmt.Errorf("expected warp payload %q, got %q", step.ExpectedWarpPayload, got)
+		}
+	}
+
+	for _, want := range step.ExpectedPostState {
+		asset, err := want.AssetID()
+		if err != nil {
+			return fmt.Errorf("invalid expectedPostState asset: %w", err)
+		}
+		addr, err := resolveAddress(actor, want.Address)
+		if err != nil {
+			return fmt.Errorf("invalid expectedPostState address: %w", err)
+		}
+		got, err := storage.GetBalance(ctx, store, addr, asset)
+		if err != nil {
+			return fmt.Errorf("reading postState balance for %s: %w", want.Address, err)
+		}
+		if got != want.Balance {
+			return fmt.Errorf("expected %s to hold %d of %s, got %d", want.Address, want.Balance, want.Asset, got)
+		}
+	}
+	return nil
+}
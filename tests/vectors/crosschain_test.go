@@ -0,0 +1,45 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vectors
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// record, when set via -record, overwrites every crosschain scenario's
+// Expected* fields with the actual outcome of each step instead of
+// asserting them, then writes the scenario back to disk. Use it after an
+// intentional protocol change to regenerate the corpus:
+//
+//	go test ./tests/vectors/... -run TestCrossChain -record
+var record = flag.Bool("record", false, "regenerate the crosschain corpus instead of asserting against it")
+
+func TestCrossChain(t *testing.T) {
+	require := require.New(t)
+
+	dir := filepath.Join("corpus", "crosschain")
+	scenarios, err := LoadScenarios(dir)
+	require.NoError(err)
+	require.NotEmpty(scenarios)
+
+	for _, s := range scenarios {
+		s := s
+		t.Run(s.Name, func(t *testing.T) {
+			require.NoError(RunScenario(context.Background(), s, *record))
+			if !*record {
+				return
+			}
+			b, err := json.MarshalIndent(s, "", "  ")
+			require.NoError(err)
+			require.NoError(os.WriteFile(s.Path(), append(b, '\n'), 0o644))
+		})
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vectors
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+
+	nconsts "github.com/nuklai/nuklaivm/consts"
+	"github.com/nuklai/nuklaivm/storage"
+
+	"github.com/nuklai/nuklaivm/actions"
+)
+
+// Run seeds an in-memory store from v.PreState, decodes and executes
+// v.Action through the same chain.Action.Execute path production
+// transactions use, and reports whether the outcome and resulting balances
+// match v.ExpectedSuccess/ExpectedOutput/ExpectedPostState.
+func Run(ctx context.Context, v *Vector) error {
+	actor, err := signerAddress(v.Signer)
+	if err != nil {
+		return fmt.Errorf("%s: invalid signer: %w", v.Name, err)
+	}
+
+	action, err := decodeAction(actor, v.ActionTypeID, v.Action, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", v.Name, err)
+	}
+
+	store := newMemStore()
+	for _, bal := range v.PreState {
+		if err := seedBalance(ctx, store, actor, bal); err != nil {
+			return fmt.Errorf("%s: seeding preState: %w", v.Name, err)
+		}
+	}
+
+	success, _, output, _, err := action.Execute(ctx, chain.Rules(nil), store, 0, actor, ids.Empty, false)
+	if err != nil {
+		return fmt.Errorf("%s: Execute returned an error (actions should signal failure via success=false): %w", v.Name, err)
+	}
+
+	if success != v.ExpectedSuccess {
+		return fmt.Errorf("%s: expected success=%t, got %t (output=%q)", v.Name, v.ExpectedSuccess, success, output)
+	}
+	if v.ExpectedOutput != "" && string(output) != v.ExpectedOutput {
+		return fmt.Errorf("%s: expected output %q, got %q", v.Name, v.ExpectedOutput, string(output))
+	}
+
+	for _, want := range v.ExpectedPostState {
+		asset, err := want.AssetID()
+		if err != nil {
+			return fmt.Errorf("%s: invalid expectedPostState asset: %w", v.Name, err)
+		}
+		addr, err := resolveAddress(actor, want.Address)
+		if err != nil {
+			return fmt.Errorf("%s: invalid expectedPostState address: %w", v.Name, err)
+		}
+		got, err := storage.GetBalance(ctx, store, addr, asset)
+		if err != nil {
+			return fmt.Errorf("%s: reading postState balance for %s: %w", v.Name, want.Address, err)
+		}
+		if got != want.Balance {
+			return fmt.Errorf("%s: expected %s to hold %d of %s, got %d", v.Name, want.Address, want.Balance, want.Asset, got)
+		}
+	}
+
+	return nil
+}
+
+// DecodeAction decodes v.Action the same way Run does, but against the
+// caller-supplied actor rather than v.Signer's own derived address. This
+// lets a live-node conformance suite replay a vector's action as a real
+// transaction signed by whichever key it has funded, while still honoring
+// "$actor" references inside the fixture.
+func DecodeAction(actor codec.Address, v *Vector) (chain.Action, error) {
+	return decodeAction(actor, v.ActionTypeID, v.Action, nil)
+}
+
+// decodeAction unmarshals raw into the concrete action struct named by
+// typeID. Only the actions whose Go structs this corpus currently has
+// fixtures for are listed; extend as more fixtures are added.
+//
+// Action fields that hold a codec.Address (e.g. BatchTransfer's "to") are
+// encoded as bech32 strings, same as genesis's CustomAllocation, rather
+// than relying on codec.Address's own (HRP-less) JSON encoding. The special
+// string "$actor" resolves to the vector's own signer, so a vector can
+// refer to itself without knowing its derived address up front.
+//
+// w carries the verified warp message for an action whose Unmarshal takes
+// one (e.g. ReceiveMessage) instead of reading its fields from raw, the
+// same way ImportAsset derives its fields from the export it's relaying
+// rather than from transaction bytes; it is nil for every other action.
+func decodeAction(actor codec.Address, typeID uint8, raw json.RawMessage, w *warp.Message) (chain.Action, error) {
+	switch typeID {
+	case (&actions.BatchTransfer{}).GetTypeID():
+		return decodeBatchTransfer(actor, raw)
+	case (&actions.SendMessage{}).GetTypeID():
+		return decodeSendMessage(actor, raw)
+	case (&actions.ReceiveMessage{}).GetTypeID():
+		if w == nil {
+			return nil, fmt.Errorf("actionTypeID %d requires a warp message", typeID)
+		}
+		return actions.UnmarshalReceiveMessage(nil, w)
+	default:
+		return nil, fmt.Errorf("unsupported actionTypeID %d", typeID)
+	}
+}
+
+// decodeSendMessage decodes a SendMessage fixture: "destination" is a
+// blockchain ID string, "receiver" a bech32 address (or "$actor"), and
+// "payload" hex-encoded bytes.
+func decodeSendMessage(actor codec.Address, raw json.RawMessage) (*actions.SendMessage, error) {
+	var fixture struct {
+		Destination string `json:"destination"`
+		Receiver    string `json:"receiver"`
+		Payload     string `json:"payload"`
+	}
+	if err := json.Unmarshal(raw, &fixture); err != nil {
+		return nil, err
+	}
+
+	destination, err := ids.FromString(fixture.Destination)
+	if err != nil {
+		return nil, fmt.Errorf("invalid destination: %w", err)
+	}
+	receiver, err := resolveAddress(actor, fixture.Receiver)
+	if err != nil {
+		return nil, fmt.Errorf("invalid receiver: %w", err)
+	}
+	payload, err := hex.DecodeString(fixture.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	return &actions.SendMessage{Destination: destination, Receiver: receiver, Payload: payload}, nil
+}
+
+func decodeBatchTransfer(actor codec.Address, raw json.RawMessage) (*actions.BatchTransfer, error) {
+	var fixture struct {
+		Transfers []struct {
+			To    string `json:"to"`
+			Asset string `json:"asset"`
+			Value uint64 `json:"value"`
+		} `json:"transfers"`
+	}
+	if err := json.Unmarshal(raw, &fixture); err != nil {
+		return nil, err
+	}
+
+	a := &actions.BatchTransfer{Transfers: make([]actions.TransferEntry, len(fixture.Transfers))}
+	for i, tr := range fixture.Transfers {
+		to, err := resolveAddress(actor, tr.To)
+		if err != nil {
+			return nil, fmt.Errorf("transfer %d: invalid to address: %w", i, err)
+		}
+		asset := ids.Empty
+		if tr.Asset != "" {
+			if asset, err = ids.FromString(tr.Asset); err != nil {
+				return nil, fmt.Errorf("transfer %d: invalid asset: %w", i, err)
+			}
+		}
+		a.Transfers[i] = actions.TransferEntry{To: to, Asset: asset, Value: tr.Value}
+	}
+	return a, nil
+}
+
+// resolveAddress parses address as bech32, except for the literal
+// "$actor", which resolves to actor, and "$echo", which resolves to the
+// crosschain corpus's echoReceiver address.
+func resolveAddress(actor codec.Address, address string) (codec.Address, error) {
+	switch address {
+	case "$actor":
+		return actor, nil
+	case "$echo":
+		return echoReceiverAddress, nil
+	}
+	return codec.ParseAddressBech32(nconsts.HRP, address)
+}
+
+func seedBalance(ctx context.Context, store *memStore, actor codec.Address, bal BalanceFixture) error {
+	asset, err := bal.AssetID()
+	if err != nil {
+		return err
+	}
+	addr, err := resolveAddress(actor, bal.Address)
+	if err != nil {
+		return err
+	}
+	return storage.SetBalance(ctx, store, addr, asset, bal.Balance)
+}
+
+// memStore is a minimal map-backed state.Mutable, good enough to drive a
+// single action's Execute without a real merkledb.
+type memStore struct {
+	mu sync.RWMutex
+	kv map[string][]byte
+}
+
+func newMemStore() *memStore {
+	return &memStore{kv: map[string][]byte{}}
+}
+
+func (m *memStore) GetValue(_ context.Context, key []byte) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.kv[string(key)]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return v, nil
+}
+
+func (m *memStore) Insert(_ context.Context, key, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.kv[string(key)] = value
+	return nil
+}
+
+func (m *memStore) Remove(_ context.Context, key []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.kv, string(key))
+	return nil
+}
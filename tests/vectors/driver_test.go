@@ -0,0 +1,26 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vectors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorpus(t *testing.T) {
+	require := require.New(t)
+
+	vectors, err := Load("corpus")
+	require.NoError(err)
+	require.NotEmpty(vectors)
+
+	for _, v := range vectors {
+		v := v
+		t.Run(v.Name, func(t *testing.T) {
+			require.NoError(Run(context.Background(), v))
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vectors
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/ava-labs/hypersdk/state"
+
+	"github.com/nuklai/nuklaivm/auth"
+	"github.com/nuklai/nuklaivm/icm"
+)
+
+// echoReceiverAddress is the icm.Receiver address a crosschain Scenario's
+// ReceiveMessage steps target to exercise successful dispatch; fixtures
+// refer to it as "$echo" rather than spelling out its bech32 form, the same
+// way they refer to a vector's own signer as "$actor".
+var echoReceiverAddress = mustAddress("00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000")
+
+func mustAddress(hexSeed string) codec.Address {
+	privBytes, err := codec.LoadHex(hexSeed, ed25519.PrivateKeyLen)
+	if err != nil {
+		panic(err)
+	}
+	priv := ed25519.PrivateKey(privBytes)
+	return auth.NewED25519Address(priv.PublicKey())
+}
+
+// echoReceiver is a test-only icm.Receiver: it stores the last payload it
+// received under the single fixed key echoReceivedKey, whatever the sender,
+// so a Scenario can assert a
+// ReceiveMessage step actually reached it and with what payload, without
+// asserting on a real program's business logic.
+type echoReceiver struct{}
+
+func (echoReceiver) StateKeys(_ []byte) []string {
+	return []string{string(echoReceivedKey)}
+}
+
+func (echoReceiver) StateKeysMaxChunks(_ []byte) []uint16 {
+	return []uint16{1}
+}
+
+func (echoReceiver) Receive(ctx context.Context, mu state.Mutable, _ ids.ID, _ codec.Address, payload []byte, _ uint64) (uint64, []byte, error) {
+	if err := mu.Insert(ctx, echoReceivedKey, payload); err != nil {
+		return 0, nil, err
+	}
+	return 1, payload, nil
+}
+
+var echoReceivedKey = []byte("vectors/echoReceiver/lastPayload")
+
+func init() {
+	icm.Register(echoReceiverAddress, echoReceiver{})
+}
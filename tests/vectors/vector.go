@@ -0,0 +1,118 @@
+// Copyright (C) 2024, AllianceBlock. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package vectors drives a versioned corpus of JSON test vectors against
+// the same chain.Action execution path production transactions use,
+// independent of any running node. Each vector names an action type,
+// encodes its pre-state as a set of balances, applies the action, and
+// asserts the resulting balances, success, and output match what's
+// expected. The corpus lives in tests/vectors/corpus so alternative
+// nuklaivm implementations can exercise it against their own execution
+// path and confirm they agree with this one. tests/vectors/corpus/crosschain
+// holds a second corpus of multi-step Scenarios (see crosschain.go) that
+// exercise a SendMessage/ReceiveMessage flow across two chains instead of
+// a single action in isolation.
+package vectors
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+	"github.com/nuklai/nuklaivm/auth"
+)
+
+// BalanceFixture seeds or asserts a single (address, asset) balance.
+type BalanceFixture struct {
+	Address string `json:"address"` // bech32
+	Asset   string `json:"asset"`   // "" means the native asset (ids.Empty)
+	Balance uint64 `json:"balance"`
+}
+
+// AssetID returns the fixture's asset, defaulting to the native asset.
+func (b BalanceFixture) AssetID() (ids.ID, error) {
+	if b.Asset == "" {
+		return ids.Empty, nil
+	}
+	return ids.FromString(b.Asset)
+}
+
+// Vector is one (preState, action, signer, expectedPostState,
+// expectedSuccess, expectedOutput) tuple from the corpus.
+type Vector struct {
+	// Name identifies the vector in test output, e.g.
+	// "batch_transfer/self_transfer".
+	Name string `json:"name"`
+
+	// ActionTypeID selects which action struct Action decodes into; see
+	// decodeAction in driver.go for the supported set.
+	ActionTypeID uint8 `json:"actionTypeID"`
+	// Action is the action's fields, encoded the same way its json tags
+	// would marshal it (e.g. {"transfers": [...]}  for BatchTransfer).
+	Action json.RawMessage `json:"action"`
+
+	// Signer is the hex-encoded ED25519 private key of the actor
+	// submitting Action.
+	Signer string `json:"signer"`
+
+	PreState          []BalanceFixture `json:"preState"`
+	ExpectedPostState []BalanceFixture `json:"expectedPostState"`
+
+	// ExpectedSuccess mirrors chain.Action.Execute's success return value.
+	ExpectedSuccess bool `json:"expectedSuccess"`
+	// ExpectedOutput, if non-empty, must equal Execute's output bytes
+	// interpreted as a string (nuklaivm actions return ASCII error
+	// strings on failure).
+	ExpectedOutput string `json:"expectedOutput"`
+}
+
+// Load reads every *.json file in dir as a Vector, sorted by file name so
+// runs are deterministic.
+func Load(dir string) ([]*Vector, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vector corpus %s: %w", dir, err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+
+	vectors := make([]*Vector, 0, len(names))
+	for _, name := range names {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read vector %s: %w", name, err)
+		}
+		v := new(Vector)
+		if err := json.Unmarshal(b, v); err != nil {
+			return nil, fmt.Errorf("failed to parse vector %s: %w", name, err)
+		}
+		if v.Name == "" {
+			v.Name = name
+		}
+		vectors = append(vectors, v)
+	}
+	return vectors, nil
+}
+
+// signerAddress derives the actor address a vector's Signer key submits
+// Action as.
+func signerAddress(signer string) (codec.Address, error) {
+	privBytes, err := codec.LoadHex(signer, ed25519.PrivateKeyLen)
+	if err != nil {
+		return codec.EmptyAddress, err
+	}
+	priv := ed25519.PrivateKey(privBytes)
+	return auth.NewED25519Address(priv.PublicKey()), nil
+}